@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// BinaryStats 存储二进制文件的统计信息
+type BinaryStats struct {
+	TotalFiles int   // 文件数
+	TotalSize  int64 // 总大小
+}
+
+// 二进制检测读取的文件头字节数
+const binarySniffSize = 8000
+
+// 无效 UTF-8 或控制字符超过该比例时视为二进制文件
+const binaryThreshold = 0.3
+
+// 读取文件内容，文件头被判定为二进制时不再读取剩余内容
+func readTextFile(path string) (content []byte, binary bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	head := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	head = head[:n]
+	if isBinary(head) {
+		return head, true, nil
+	}
+
+	rest, err := io.ReadAll(file)
+	if err != nil {
+		return nil, false, err
+	}
+	return append(head, rest...), false, nil
+}
+
+// 根据内容判断是否是二进制文件：包含 NUL 字节，或无效 UTF-8 与控制字符比例过高
+// 包含 NUL 字节时只有 UTF-16 文本不视为二进制文件，其他情况下 GBK 等可以解码的文本也不视为二进制文件
+func isBinary(sample []byte) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return !isUTF16Text(sample)
+	}
+	if suspiciousRatio(sample) <= binaryThreshold {
+		return false
+	}
+	return !isEncodedText(sample)
+}
+
+// 计算无效 UTF-8 与控制字符在采样中的比例
+func suspiciousRatio(sample []byte) float64 {
+	if len(sample) == 0 {
+		return 0
+	}
+	var suspicious int
+	for i := 0; i < len(sample); {
+		r, size := utf8.DecodeRune(sample[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			// 采样末尾被截断的多字节字符不计入
+			if !utf8.FullRune(sample[i:]) {
+				i = len(sample)
+				continue
+			}
+			suspicious++
+		case r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f' && r != '\v' && r != 0x1b:
+			suspicious++
+		}
+		i += size
+	}
+	return float64(suspicious) / float64(len(sample))
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	utf16LE := func(s string) []byte {
+		var b []byte
+		for _, c := range []byte(s) {
+			b = append(b, c, 0)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name   string
+		sample []byte
+		want   bool
+	}{
+		{"empty", nil, false},
+		{"ascii text", []byte("package main\n\nfunc main() {}\n"), false},
+		{"nul separated ascii", []byte("alpha\x00beta\x00gamma\x00delta\x00"), true},
+		{"nul in ascii text", bytes.Repeat([]byte("key=value\x00\n"), 20), true},
+		{"utf-16le without bom", utf16LE("hello world\nsecond line\n"), false},
+		{"utf-16le with bom", append([]byte{0xff, 0xfe}, utf16LE("hi\n")...), false},
+		{"gbk text", []byte("\xc4\xe3\xba\xc3\xa3\xac\xca\xc0\xbd\xe7\n"), false},
+		{"control bytes", bytes.Repeat([]byte{0x01, 0x02, 0x03, 'a'}, 50), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.sample); got != tt.want {
+				t.Errorf("isBinary(%q) = %v, want %v", tt.sample, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// FunctionStats 存储单个函数的统计
+type FunctionStats struct {
+	Name       string // 函数名，方法形如 (*T).Name
+	Line       int    // 函数定义所在的行号
+	Lines      int    // 函数的行数
+	Cyclomatic int    // 圈复杂度
+	Cognitive  int    // 认知复杂度
+}
+
+// 计算函数的圈复杂度：1 + 分支语句数 + 逻辑运算符数，函数字面量计入所在函数
+func cyclomaticComplexity(fn *ast.FuncDecl) int {
+	complexity := 1
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			// default 分支不增加复杂度
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// 计算函数的认知复杂度
+// 分支和循环语句按嵌套深度额外加分，连续相同的逻辑运算符只计一次，递归调用和带标签的跳转各计一次
+func cognitiveComplexity(fn *ast.FuncDecl) int {
+	v := &cognitiveVisitor{
+		elseIf:  make(map[*ast.IfStmt]bool),
+		counted: make(map[*ast.BinaryExpr]bool),
+	}
+	// 只有普通函数才能通过名称识别递归调用
+	if fn.Recv == nil {
+		v.name = fn.Name.Name
+	}
+	if fn.Body != nil {
+		ast.Walk(v, fn.Body)
+	}
+	return v.score
+}
+
+// cognitiveVisitor 遍历函数体并累计认知复杂度
+type cognitiveVisitor struct {
+	name    string // 函数名，用于识别递归调用
+	nesting int    // 当前嵌套深度
+	score   int    // 累计的认知复杂度
+
+	elseIf  map[*ast.IfStmt]bool     // 作为 else if 出现的 if 语句
+	counted map[*ast.BinaryExpr]bool // 已计入的逻辑表达式
+}
+
+func (v *cognitiveVisitor) Visit(n ast.Node) ast.Visitor {
+	switch n := n.(type) {
+	case *ast.IfStmt:
+		if !v.elseIf[n] {
+			v.score += 1 + v.nesting
+		}
+		v.walk(n.Init, n.Cond)
+		v.nested(n.Body)
+		switch e := n.Else.(type) {
+		case *ast.IfStmt:
+			// else if 只加一分，不随嵌套深度增加
+			v.score++
+			v.elseIf[e] = true
+			v.walk(e)
+		case *ast.BlockStmt:
+			v.score++
+			v.nested(e)
+		}
+		return nil
+
+	case *ast.SwitchStmt:
+		v.score += 1 + v.nesting
+		v.walk(n.Init, n.Tag)
+		v.nested(n.Body)
+		return nil
+
+	case *ast.TypeSwitchStmt:
+		v.score += 1 + v.nesting
+		v.walk(n.Init, n.Assign)
+		v.nested(n.Body)
+		return nil
+
+	case *ast.SelectStmt:
+		v.score += 1 + v.nesting
+		v.nested(n.Body)
+		return nil
+
+	case *ast.ForStmt:
+		v.score += 1 + v.nesting
+		v.walk(n.Init, n.Cond, n.Post)
+		v.nested(n.Body)
+		return nil
+
+	case *ast.RangeStmt:
+		v.score += 1 + v.nesting
+		v.walk(n.Key, n.Value, n.X)
+		v.nested(n.Body)
+		return nil
+
+	case *ast.FuncLit:
+		// 函数字面量增加嵌套深度，但本身不加分
+		v.nested(n.Body)
+		return nil
+
+	case *ast.BranchStmt:
+		if n.Tok == token.GOTO || (n.Label != nil && n.Tok != token.FALLTHROUGH) {
+			v.score++
+		}
+
+	case *ast.BinaryExpr:
+		if (n.Op == token.LAND || n.Op == token.LOR) && !v.counted[n] {
+			v.score += v.logicalSequences(n)
+		}
+
+	case *ast.CallExpr:
+		if ident, ok := n.Fun.(*ast.Ident); ok && v.name != "" && ident.Name == v.name {
+			v.score++
+		}
+	}
+	return v
+}
+
+// 在当前嵌套深度遍历节点
+func (v *cognitiveVisitor) walk(nodes ...ast.Node) {
+	for _, n := range nodes {
+		if n != nil {
+			ast.Walk(v, n)
+		}
+	}
+}
+
+// 嵌套深度加一后遍历节点
+func (v *cognitiveVisitor) nested(n ast.Node) {
+	v.nesting++
+	v.walk(n)
+	v.nesting--
+}
+
+// 统计逻辑表达式中相同运算符组成的序列数，如 a && b && c || d 为 2
+func (v *cognitiveVisitor) logicalSequences(expr *ast.BinaryExpr) int {
+	var ops []token.Token
+	var collect func(e ast.Expr)
+	collect = func(e ast.Expr) {
+		b, ok := e.(*ast.BinaryExpr)
+		if !ok || (b.Op != token.LAND && b.Op != token.LOR) {
+			return
+		}
+		v.counted[b] = true
+		collect(b.X)
+		ops = append(ops, b.Op)
+		collect(b.Y)
+	}
+	collect(expr)
+
+	sequences := 0
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			sequences++
+		}
+	}
+	return sequences
+}
+
+// 获取函数名，方法的名称包含接收者类型
+func functionName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return "(" + receiverType(fn.Recv.List[0].Type) + ")." + fn.Name.Name
+}
+
+// 获取接收者的类型名，忽略类型参数
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + receiverType(t.X)
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	case *ast.IndexListExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return "?"
+}
+
+// 按词法估算复杂度时计入的分支关键字
+var branchKeywords = map[string]bool{
+	"if": true, "elif": true, "elsif": true, "elseif": true, "unless": true,
+	"for": true, "foreach": true, "while": true, "until": true,
+	"case": true, "catch": true, "except": true, "rescue": true, "guard": true,
+}
+
+// 使用 and、or 作为逻辑运算符的语言
+var wordOperatorLanguages = []string{"Python", "Starlark", "Ruby", "Perl", "Lua", "Elixir", "PowerShell", "SQL"}
+
+// 关键字不区分大小写的语言
+var caseInsensitiveLanguages = []string{"SQL", "PowerShell", "Batch"}
+
+// 标记语言和数据格式中的关键字不表示分支，不估算复杂度
+var markupLanguages = []string{
+	"HTML", "Vue", "Svelte", "XML", "Markdown", "CSS", "SCSS", "LESS",
+	"JSON", "YAML", "Text", "Dockerfile", NotebookLanguage,
+}
+
+// branchRules 是估算复杂度时与语言相关的规则
+type branchRules struct {
+	wordOperators bool // 是否将 and、or 视为逻辑运算符
+	ignoreCase    bool // 关键字是否不区分大小写
+}
+
+// 获取语言的复杂度估算规则，标记语言和数据格式返回 nil
+func newBranchRules(language string) *branchRules {
+	if slices.Contains(markupLanguages, language) {
+		return nil
+	}
+	return &branchRules{
+		wordOperators: slices.Contains(wordOperatorLanguages, language),
+		ignoreCase:    slices.Contains(caseInsensitiveLanguages, language),
+	}
+}
+
+// 统计一行代码中的分支关键字和逻辑运算符，code 中已去除注释和字符串字面量
+func (r *branchRules) count(code string) int {
+	count := strings.Count(code, "&&") + strings.Count(code, "||")
+	for i := 0; i < len(code); {
+		if !isIdentStart(code[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(code) && (isIdentStart(code[i]) || code[i] >= '0' && code[i] <= '9') {
+			i++
+		}
+		// 跳过成员访问，如 obj.for、$if
+		if start > 0 && (code[start-1] == '.' || code[start-1] == '$') {
+			continue
+		}
+
+		word := code[start:i]
+		if r.ignoreCase {
+			word = strings.ToLower(word)
+		}
+		if branchKeywords[word] || (r.wordOperators && (word == "and" || word == "or")) {
+			count++
+		}
+	}
+	return count
+}
+
+// 判断是否可以作为标识符的开始
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+)
+
+// DefaultLanguageConfigFile 被分析目录中自动加载的语言配置文件名
+const DefaultLanguageConfigFile = ".code-stats.json"
+
+// LanguageConfig 用户自定义语言配置文件的格式
+type LanguageConfig struct {
+	Languages []LanguageDefinition `json:"languages"`
+}
+
+// LanguageDefinition 描述一种用户自定义语言
+// 与内置语言同名时覆盖内置定义；未指定任何注释或字符串语法时保留内置的注释样式
+type LanguageDefinition struct {
+	Name       string        `json:"name"`       // 语言名称
+	Extensions []string      `json:"extensions"` // 文件扩展名，如 .thrift
+	Filenames  []string      `json:"filenames"`  // 完整文件名，如 BUILD
+	SingleLine []string      `json:"singleLine"` // 单行注释标记
+	MultiStart []string      `json:"multiStart"` // 多行注释开始标记
+	MultiEnd   []string      `json:"multiEnd"`   // 多行注释结束标记
+	Nested     bool          `json:"nested"`     // 多行注释是否可以嵌套
+	LineStart  []string      `json:"lineStart"`  // 仅在行首有效的注释标记
+	Strings    []StringStyle `json:"strings"`    // 字符串字面量定界符
+}
+
+// languageTables 是语言识别使用的全局映射的副本
+type languageTables struct {
+	ext       map[string]string
+	filenames map[string]string
+	patterns  map[string]CommentStyle
+}
+
+// 保存当前的语言映射
+func saveLanguageTables() languageTables {
+	return languageTables{
+		ext:       maps.Clone(languageExt),
+		filenames: maps.Clone(languageFilenames),
+		patterns:  maps.Clone(CommentPatterns),
+	}
+}
+
+// 恢复保存的语言映射，撤销之后加载的所有自定义语言
+func (t languageTables) restore() {
+	languageExt = t.ext
+	languageFilenames = t.filenames
+	CommentPatterns = t.patterns
+}
+
+// LoadLanguageConfig 从 JSON 文件加载自定义语言，并合并到内置的语言定义中
+// 所有定义都有效时才会合并，任何一个定义无效时不修改已有的语言定义
+func LoadLanguageConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取语言配置失败: %v", err)
+	}
+
+	var config LanguageConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("解析语言配置失败: %s (%v)", path, err)
+	}
+
+	for _, def := range config.Languages {
+		if err := def.validate(); err != nil {
+			return fmt.Errorf("语言配置无效: %s (%v)", path, err)
+		}
+	}
+
+	saved := saveLanguageTables()
+	for _, def := range config.Languages {
+		def.register()
+	}
+
+	// 合并后重新自检，失败时撤销本文件中的所有定义
+	if err := CheckCommentPatterns(); err != nil {
+		saved.restore()
+		return err
+	}
+	return nil
+}
+
+// 获取语言定义中的注释样式
+func (d LanguageDefinition) style() CommentStyle {
+	return CommentStyle{
+		SingleLine: d.SingleLine,
+		MultiStart: d.MultiStart,
+		MultiEnd:   d.MultiEnd,
+		Nested:     d.Nested,
+		LineStart:  d.LineStart,
+		Strings:    d.Strings,
+	}
+}
+
+// 检查语言定义是否有效
+func (d LanguageDefinition) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("语言名称不能为空")
+	}
+	if err := d.style().validate(); err != nil {
+		return fmt.Errorf("%s: %v", d.Name, err)
+	}
+	return nil
+}
+
+// 将语言定义注册到全局映射中，调用前需要通过 validate 检查
+func (d LanguageDefinition) register() {
+
+	for _, ext := range d.Extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		languageExt[ext] = d.Name
+	}
+	for _, name := range d.Filenames {
+		if name = strings.TrimSpace(name); name != "" {
+			languageFilenames[name] = d.Name
+		}
+	}
+
+	// 只扩展了文件映射的内置语言，保留原有的注释样式
+	_, builtin := CommentPatterns[d.Name]
+	hasSyntax := len(d.SingleLine) > 0 || len(d.MultiStart) > 0 || len(d.Strings) > 0
+	if builtin && !hasSyntax {
+		return
+	}
+
+	CommentPatterns[d.Name] = d.style()
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLanguageConfigRejectsEmptyMarkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{
+			name:    "empty string delimiters",
+			config:  `{"languages":[{"name":"EmptyString","extensions":[".es1"],"strings":[{"start":"","end":""}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty string end",
+			config:  `{"languages":[{"name":"EmptyStringEnd","extensions":[".es2"],"strings":[{"start":"\"","end":""}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty multi-line markers",
+			config:  `{"languages":[{"name":"EmptyMulti","extensions":[".em"],"multiStart":[""],"multiEnd":[""]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty single-line marker",
+			config:  `{"languages":[{"name":"EmptySingle","extensions":[".esl"],"singleLine":["#",""]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced multi-line markers",
+			config:  `{"languages":[{"name":"Unbalanced","extensions":[".ub"],"multiStart":["/*"]}]}`,
+			wantErr: true,
+		},
+		{
+			name:   "valid definition",
+			config: `{"languages":[{"name":"ValidLang","extensions":[".vl"],"singleLine":["#"],"strings":[{"start":"\"","end":"\"","escape":"\\"}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(saveLanguageTables().restore)
+			path := filepath.Join(t.TempDir(), DefaultLanguageConfigFile)
+			if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := LoadLanguageConfig(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadLanguageConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLanguageConfigIsAtomic(t *testing.T) {
+	t.Cleanup(saveLanguageTables().restore)
+
+	// 第二个定义无效，第一个定义也不应生效
+	config := `{"languages":[` +
+		`{"name":"FirstLang","extensions":[".first"],"filenames":["FIRSTFILE"],"singleLine":["#"]},` +
+		`{"name":"BrokenLang","extensions":[".broken"],"singleLine":[""]}]}`
+	path := filepath.Join(t.TempDir(), DefaultLanguageConfigFile)
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadLanguageConfig(path); err == nil {
+		t.Fatal("LoadLanguageConfig() error = nil, want error")
+	}
+	if lang := GetLanguageByExt("a.first"); lang != "Unknown" {
+		t.Errorf("GetLanguageByExt(a.first) = %q, want Unknown", lang)
+	}
+	if _, ok := languageFilenames["FIRSTFILE"]; ok {
+		t.Error("FIRSTFILE registered from an invalid config")
+	}
+	if _, ok := CommentPatterns["FirstLang"]; ok {
+		t.Error("FirstLang registered from an invalid config")
+	}
+}
+
+func TestAnalyzeDirectoryRestoresLanguages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		DefaultLanguageConfigFile: `{"languages":[{"name":"RepoLang","extensions":[".repo"],"singleLine":["--"]}]}`,
+		"a.repo":                  "-- comment\ncode\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := AnalyzeDirectory(dir, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lang := stats.LanguageStats["RepoLang"]
+	if lang == nil || lang.CodeLines != 1 || lang.CommentLines != 1 {
+		t.Fatalf("RepoLang stats = %+v, want 1 code line and 1 comment line", lang)
+	}
+
+	// 分析结束后仓库内的语言定义不再生效
+	if lang := GetLanguageByExt("b.repo"); lang != "Unknown" {
+		t.Errorf("GetLanguageByExt(b.repo) = %q after analysis, want Unknown", lang)
+	}
+	if _, ok := CommentPatterns["RepoLang"]; ok {
+		t.Error("RepoLang still registered after analysis")
+	}
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// lineCounter 统计某一种语言的行数，每种语言使用独立的词法分析器
+type lineCounter struct {
+	language string
+	stat     *Stat
+	style    CommentStyle
+	hasStyle bool // 是否定义了注释样式
+	prose    bool // 是否是文档内容，非空行全部视为文档行
+	lex      *lexer
+	docs     *docstringTracker // 文档字符串识别，不支持文档字符串的语言为空
+	branches *branchRules      // 复杂度估算规则，不估算复杂度的语言为空
+	funcs    functionTracker   // 函数边界识别，不支持的语言为空
+	nesting  nestingTracker    // 嵌套深度计算，不支持的语言为空
+
+	lastLine  int              // 最后统计的行号
+	functions []*FunctionStats // 已结束的函数
+	codeLines []codeLine       // 规范化后的代码行，用于检测重复代码
+	markers   []*CommentMarker // 注释中的标记
+}
+
+// 创建指定语言的行计数器
+func newLineCounter(language string) *lineCounter {
+	style, hasStyle := CommentPatterns[language]
+	return &lineCounter{
+		language: language,
+		stat:     &Stat{},
+		style:    style,
+		hasStyle: hasStyle,
+		lex:      newLexer(style),
+		docs:     newDocstringTracker(language),
+		branches: newBranchRules(language),
+		funcs:    newFunctionTracker(language),
+		nesting:  newNestingTracker(language),
+	}
+}
+
+// 创建文档内容的行计数器，如 Notebook 中的 Markdown 单元格
+func newProseCounter(language string) *lineCounter {
+	c := newLineCounter(language)
+	c.prose = true
+	return c
+}
+
+// 重置词法状态，用于重新进入同一种语言的新区域
+func (c *lineCounter) reset() {
+	c.lex = newLexer(c.style)
+	if c.nesting != nil {
+		c.nesting = newNestingTracker(c.language)
+	}
+	if c.docs != nil {
+		c.docs.reset()
+	}
+	c.finishFunctions()
+}
+
+// 结束未结束的函数并记录函数长度
+func (c *lineCounter) finishFunctions() {
+	if c.funcs == nil {
+		return
+	}
+	for _, fn := range c.funcs.finish(c.lastLine) {
+		c.stat.addFunction(fn.Lines)
+		c.functions = append(c.functions, fn)
+	}
+}
+
+// 统计一行，lineNo 为该行在文件中的行号
+func (c *lineCounter) count(line string, lineNo int, options FileAnalyzerOptions) {
+	s := c.stat
+	c.lastLine = lineNo
+	trimmedLine := strings.TrimSpace(line)
+
+	// 按字符而不是字节计算长度
+	lineLength := utf8.RuneCountInString(line)
+	s.TotalChars += lineLength
+
+	// 统计最长行和超长行
+	s.MaxLineLength = max(s.MaxLineLength, lineLength)
+	if options.LongLineThreshold > 0 && lineLength > options.LongLineThreshold {
+		s.LongLines++
+	}
+
+	// 统计行数
+	s.TotalLines++
+
+	// 统计空白行数
+	if trimmedLine == "" {
+		s.BlankLines++
+		return
+	}
+
+	// 文档内容不区分代码和注释
+	if c.prose {
+		s.DocLines++
+		return
+	}
+
+	// 如果找不到当前语言的注释样式，则全部视为代码行
+	if !c.hasStyle {
+		s.CodeLines++
+		return
+	}
+
+	inString := c.lex.mode == modeString
+	res := c.lex.scanLine(line)
+	if res.hasComment {
+		if marker := findMarker(res.comment, options.CommentMarkers); marker != nil {
+			marker.Line = lineNo
+			marker.Language = c.language
+			c.markers = append(c.markers, marker)
+			s.Markers++
+		}
+	}
+	if c.funcs != nil {
+		c.funcs.line(lineNo, line, res, inString)
+	}
+	if c.nesting != nil {
+		if depth := c.nesting.depth(line, res, inString); depth >= 0 {
+			s.MaxNestingDepth = max(s.MaxNestingDepth, depth)
+			s.TotalNestingDepth += depth
+			s.NestedLines++
+		}
+	}
+	if options.DuplicateWindow > 0 && res.hasCode {
+		if hash, ok := normalizeLine(res.stripped); ok {
+			c.codeLines = append(c.codeLines, codeLine{lineNo: lineNo, hash: hash})
+		}
+	}
+	if c.docs != nil && c.docs.isDoc(trimmedLine, res, c.lex.mode == modeString) {
+		s.DocLines++
+		return
+	}
+
+	// 根据分支关键字和逻辑运算符估算复杂度
+	if c.branches != nil && res.hasCode {
+		s.Complexity += c.branches.count(res.code)
+	}
+
+	switch {
+	case res.hasCode && res.hasComment:
+		// 混合行按配置的方式计数
+		s.MixedLines++
+		switch options.MixedLinePolicy {
+		case MixedAsComment:
+			s.CommentLines++
+		case MixedAsBoth:
+			s.CodeLines++
+			s.CommentLines++
+		default:
+			s.CodeLines++
+		}
+	case res.hasComment:
+		s.CommentLines++
+	default:
+		s.CodeLines++
+	}
+}
@@ -0,0 +1,290 @@
+package analyzer
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DetectLanguage 分层识别文件的编程语言
+// 依次检查完整文件名、shebang、Vim/Emacs modeline 和扩展名，歧义扩展名（如 .h、.m）根据内容判断
+// content 为文件内容，为空时只根据文件名判断
+func DetectLanguage(path string, content []byte) string {
+	// 完整文件名，如 Makefile、Dockerfile
+	if lang, ok := languageFilenames[filepath.Base(path)]; ok {
+		return lang
+	}
+
+	head, tail := headAndTailLines(content, modelineSearchLines)
+
+	// shebang，如 #!/usr/bin/env python3
+	if len(head) > 0 {
+		if lang := detectShebang(head[0]); lang != "" {
+			return lang
+		}
+	}
+
+	// modeline，如 # vim: set ft=python: 或 -*- mode: ruby -*-
+	for _, line := range slices.Concat(head, tail) {
+		if lang := detectModeline(line); lang != "" {
+			return lang
+		}
+	}
+
+	// 扩展名，歧义扩展名先根据内容判断
+	ext := strings.ToLower(filepath.Ext(path))
+	if resolve, ok := ambiguousExt[ext]; ok && len(content) > 0 {
+		if lang := resolve(content); lang != "" {
+			return lang
+		}
+	}
+	return GetLanguageByExt(path)
+}
+
+// LookupLanguage 根据名称或别名查找语言，如 golang、py、c++，找不到时返回空字符串
+func LookupLanguage(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ""
+	}
+	if lang, ok := languageAliases[name]; ok {
+		return lang
+	}
+	for lang := range CommentPatterns {
+		if strings.ToLower(lang) == name {
+			return lang
+		}
+	}
+	if lang, ok := languageExt["."+name]; ok {
+		return lang
+	}
+	return ""
+}
+
+// 文件名到语言的映射，优先于其他规则
+var languageFilenames = map[string]string{
+	"Makefile":        "Makefile",
+	"makefile":        "Makefile",
+	"GNUmakefile":     "Makefile",
+	"Dockerfile":      "Dockerfile",
+	"Containerfile":   "Dockerfile",
+	"Jenkinsfile":     "Groovy",
+	"BUILD":           "Starlark",
+	"BUILD.bazel":     "Starlark",
+	"WORKSPACE":       "Starlark",
+	"WORKSPACE.bazel": "Starlark",
+	"MODULE.bazel":    "Starlark",
+	"CMakeLists.txt":  "CMake",
+	"Gemfile":         "Ruby",
+	"Rakefile":        "Ruby",
+	"Vagrantfile":     "Ruby",
+	"Podfile":         "Ruby",
+	".bashrc":         "Shell",
+	".bash_profile":   "Shell",
+	".zshrc":          "Shell",
+	".profile":        "Shell",
+	"build.gradle":    "Groovy",
+	"settings.gradle": "Groovy",
+}
+
+// shebang 解释器到语言的映射，解释器名称已去除版本号
+var shebangInterpreters = map[string]string{
+	"sh":         "Shell",
+	"bash":       "Shell",
+	"zsh":        "Shell",
+	"ksh":        "Shell",
+	"dash":       "Shell",
+	"ash":        "Shell",
+	"python":     "Python",
+	"node":       "JavaScript",
+	"nodejs":     "JavaScript",
+	"ts-node":    "TypeScript",
+	"ruby":       "Ruby",
+	"perl":       "Perl",
+	"php":        "PHP",
+	"lua":        "Lua",
+	"Rscript":    "R",
+	"julia":      "Julia",
+	"pwsh":       "PowerShell",
+	"powershell": "PowerShell",
+	"escript":    "Erlang",
+	"elixir":     "Elixir",
+	"make":       "Makefile",
+	"swift":      "Swift",
+	"groovy":     "Groovy",
+	"runhaskell": "Haskell",
+	"runghc":     "Haskell",
+	"ocaml":      "OCaml",
+	"scala":      "Scala",
+}
+
+// 语言别名，用于 modeline、.gitattributes 等只给出语言名称的场景
+var languageAliases = map[string]string{
+	"golang":      "Go",
+	"js":          "JavaScript",
+	"node":        "JavaScript",
+	"ts":          "TypeScript",
+	"jsx":         "React JSX",
+	"tsx":         "React TSX",
+	"py":          "Python",
+	"python3":     "Python",
+	"rb":          "Ruby",
+	"cpp":         "C++",
+	"cxx":         "C++",
+	"cs":          "C#",
+	"csharp":      "C#",
+	"rs":          "Rust",
+	"kt":          "Kotlin",
+	"hs":          "Haskell",
+	"ml":          "OCaml",
+	"ex":          "Elixir",
+	"erl":         "Erlang",
+	"clj":         "Clojure",
+	"pl":          "Perl",
+	"sh":          "Shell",
+	"bash":        "Shell",
+	"zsh":         "Shell",
+	"shell":       "Shell",
+	"ps1":         "PowerShell",
+	"bat":         "Batch",
+	"cmd":         "Batch",
+	"yml":         "YAML",
+	"md":          "Markdown",
+	"objc":        "Objective-C",
+	"objective-c": "Objective-C",
+	"octave":      "MATLAB",
+	"make":        "Makefile",
+	"docker":      "Dockerfile",
+	"bzl":         "Starlark",
+	"bazel":       "Starlark",
+	"text":        "Text",
+}
+
+// 歧义扩展名的内容判断函数，返回空字符串表示无法判断
+var ambiguousExt = map[string]func(content []byte) string{
+	".h": detectHeaderLanguage,
+	".m": detectMLanguage,
+}
+
+// 在文件开头和结尾搜索 modeline 的行数
+const modelineSearchLines = 5
+
+// 内容判断时最多检查的字节数
+const heuristicLimit = 64 * 1024
+
+var (
+	vimModeline   = regexp.MustCompile(`(?:^|\s)(?:vim?|ex):.*?\b(?:ft|filetype|syntax|syn)=([\w+#.-]+)`)
+	emacsModeline = regexp.MustCompile(`-\*-(.+?)-\*-`)
+
+	objcPattern   = regexp.MustCompile(`(?m)^\s*(?:@interface|@implementation|@protocol|@end|#import)\b`)
+	cppPattern    = regexp.MustCompile(`(?m)^\s*(?:class|namespace|template)\b|\bstd::|^\s*(?:public|private|protected):`)
+	matlabPattern = regexp.MustCompile(`(?m)^\s*(?:function\b|%)`)
+)
+
+// 获取文件开头和结尾的若干行
+func headAndTailLines(content []byte, n int) (head, tail []string) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(limitBytes(content)), "\n")
+	if len(lines) <= n {
+		return lines, nil
+	}
+	head = lines[:n]
+
+	// 只在整个文件都被读取时检查结尾
+	if len(content) <= heuristicLimit {
+		tail = lines[max(n, len(lines)-n):]
+	}
+	return head, tail
+}
+
+// 根据 shebang 行识别语言
+func detectShebang(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// 跳过 env 的选项和环境变量，如 #!/usr/bin/env -S VAR=1 python3 -u
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+				continue
+			}
+			interpreter = filepath.Base(field)
+			break
+		}
+	}
+
+	// 去除版本号，如 python3.11、lua5.4
+	interpreter = strings.TrimRight(interpreter, "0123456789.")
+	return shebangInterpreters[interpreter]
+}
+
+// 根据 Vim 或 Emacs 的 modeline 识别语言
+func detectModeline(line string) string {
+	if m := vimModeline.FindStringSubmatch(line); m != nil {
+		return LookupLanguage(m[1])
+	}
+
+	m := emacsModeline.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+
+	// -*- mode: python; coding: utf-8 -*- 或 -*- python -*-
+	for _, part := range strings.Split(m[1], ";") {
+		key, value, found := strings.Cut(part, ":")
+		if !found {
+			return LookupLanguage(key)
+		}
+		if strings.TrimSpace(strings.ToLower(key)) == "mode" {
+			return LookupLanguage(value)
+		}
+	}
+	return ""
+}
+
+// 判断 .h 文件是 C、C++ 还是 Objective-C 头文件，没有 C++ 和 Objective-C 特征的头文件视为 C
+// 空文件无法判断，仍按 C/C++ Header 统计
+func detectHeaderLanguage(content []byte) string {
+	content = limitBytes(content)
+	switch {
+	case objcPattern.Match(content):
+		return "Objective-C"
+	case cppPattern.Match(content):
+		return "C++ Header"
+	case len(bytes.TrimSpace(content)) > 0:
+		return "C"
+	}
+	return ""
+}
+
+// 判断 .m 文件是 Objective-C 还是 MATLAB
+func detectMLanguage(content []byte) string {
+	content = limitBytes(content)
+	switch {
+	case objcPattern.Match(content), bytes.Contains(content, []byte("#include")):
+		return "Objective-C"
+	case matlabPattern.Match(content):
+		return "MATLAB"
+	}
+	return ""
+}
+
+// 截取内容判断所需的前缀
+func limitBytes(content []byte) []byte {
+	if len(content) > heuristicLimit {
+		return content[:heuristicLimit]
+	}
+	return content
+}
@@ -0,0 +1,25 @@
+package analyzer
+
+import "testing"
+
+func TestDetectHeaderLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain c", "#ifndef FOO_H\n#define FOO_H\nint foo(void);\n#endif\n", "C"},
+		{"c++ class", "#pragma once\nnamespace foo {\nclass Bar {};\n}\n", "C++ Header"},
+		{"objective-c interface", "#import <Foundation/Foundation.h>\n@interface Foo : NSObject\n@end\n", "Objective-C"},
+		{"empty", "", "C/C++ Header"},
+		{"whitespace only", "\n\n", "C/C++ Header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguage("include/foo.h", []byte(tt.content)); got != tt.want {
+				t.Errorf("DetectLanguage(foo.h) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 
@@ -13,10 +14,22 @@ import (
 
 // DirectoryAnalyzerOptions 配置目录分析器的选项
 type DirectoryAnalyzerOptions struct {
+	FileAnalyzerOptions // 文件分析选项
+
 	ExcludeDirs []string // 排除的目录
 	ExcludeExt  []string // 排除的文件扩展名
 	MaxWorkers  int      // 最大并发数
 	FollowLinks bool     // 是否跟踪符号链接
+
+	ExcludeGenerated     bool // 排除生成的代码（文件头标记、文件名模式或 linguist-generated）
+	ExcludeVendored      bool // 排除 linguist-vendored 标记的第三方代码
+	ExcludeDocumentation bool // 排除 linguist-documentation 标记的文档
+	ExcludeMinified      bool // 排除压缩或打包后的文件（如 *.min.js）
+
+	TestPatterns       []string // 额外的测试文件名模式（如 *_it.go），以 / 结尾的模式匹配目录名（如 e2e/）
+	SkipDuplicateFiles bool     // 内容相同的文件只统计一次（与 cloc 默认行为一致）
+
+	LanguageConfig string // 自定义语言配置文件，在目录内的 .code-stats.json 之后加载
 }
 
 type DirectoryStats struct {
@@ -27,6 +40,32 @@ type DirectoryStats struct {
 	LanguageStats  map[string]*LanguageStats
 	ExtensionStats map[string]*ExtensionStats
 	GitStats       *GitStats // Git 仓库统计信息
+
+	GeneratedStats   *Stat // 生成代码的统计
+	HandwrittenStats *Stat // 手写代码的统计
+	MinifiedStats    *Stat // 压缩或打包文件的统计
+
+	TestSplit                      // 测试代码与非测试代码的统计
+	TestDirs  map[string]TestSplit // 按顶层目录分开统计测试代码与非测试代码，根目录下的文件为 .
+
+	BinaryStats map[string]*BinaryStats // 按扩展名统计的二进制文件，不计入行数统计
+
+	EncodingStats    map[string]int // 按编码统计的文件数
+	UndecodableFiles []string       // 无法解码的文件，不计入统计
+
+	Duplication    *DuplicationStats   // 重复代码统计
+	DuplicateFiles *DuplicateFileStats // 内容完全相同的文件
+
+	Markers    []*CommentMarker // 所有注释标记，按文件和行号排列
+	MarkerTags map[string]int   // 按标记名统计的标记数
+	MarkerDirs map[string]int   // 按目录统计的标记数，键为相对于分析目录的路径
+
+	LicenseStats   map[string]int // 按许可证统计的文件数
+	MissingLicense []string       // 缺少许可证头的源代码文件，不含生成、第三方和压缩的文件
+	LicenseFiles   []*LicenseFile // 许可证文件，仓库根目录的文件在前
+
+	GoStats    *GoStats                   // 所有 Go 文件的结构统计
+	GoPackages map[string]*GoPackageStats // 按包统计的 Go 代码，键为包目录和包名
 }
 
 func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*DirectoryStats, error) {
@@ -36,6 +75,27 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 		FileStats:      make([]*FileStats, 0),
 		LanguageStats:  make(map[string]*LanguageStats),
 		ExtensionStats: make(map[string]*ExtensionStats),
+
+		GeneratedStats:   &Stat{},
+		HandwrittenStats: &Stat{},
+		MinifiedStats:    &Stat{},
+
+		TestSplit: newTestSplit(),
+		TestDirs:  make(map[string]TestSplit),
+
+		BinaryStats:   make(map[string]*BinaryStats),
+		EncodingStats: make(map[string]int),
+
+		Duplication:    &DuplicationStats{},
+		DuplicateFiles: &DuplicateFileStats{},
+
+		MarkerTags: make(map[string]int),
+		MarkerDirs: make(map[string]int),
+
+		LicenseStats: make(map[string]int),
+
+		GoStats:    &GoStats{},
+		GoPackages: make(map[string]*GoPackageStats),
 	}
 
 	// 检查目录是否存在
@@ -46,6 +106,25 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 		return res, fmt.Errorf("不是目录: %s", path)
 	}
 
+	// 自定义语言只在本次分析中生效，分析结束后恢复原有的语言定义
+	defer saveLanguageTables().restore()
+
+	// 加载仓库内的自定义语言配置
+	if repoConfig := filepath.Join(path, DefaultLanguageConfigFile); isRegularFile(repoConfig) {
+		if err := LoadLanguageConfig(repoConfig); err != nil {
+			return res, err
+		}
+		PrintInfo("已加载语言配置: %s", repoConfig)
+	}
+
+	// 命令行指定的配置覆盖仓库内的配置
+	if options.LanguageConfig != "" {
+		if err := LoadLanguageConfig(options.LanguageConfig); err != nil {
+			return res, err
+		}
+		PrintInfo("已加载语言配置: %s", options.LanguageConfig)
+	}
+
 	// 始终分析 Git 仓库信息，忽略选项设
 	gitStats, err := AnalyzeGitRepo(path)
 	if err != nil {
@@ -55,7 +134,7 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 	}
 
 	// 遍历目录
-	var filePaths []string
+	var filePaths, attributeFiles []string
 	if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			PrintError("无法访问: %s (%v)", path, err)
@@ -85,6 +164,20 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 			return nil
 		}
 
+		// 记录 .gitattributes 文件，遍历完成后统一解析
+		if info.Name() == ".gitattributes" {
+			attributeFiles = append(attributeFiles, path)
+		}
+
+		// 识别许可证文件
+		if isLicenseFile(info.Name()) {
+			if license, err := analyzeLicenseFile(path); err != nil {
+				PrintWarning("无法读取许可证文件: %s (%v)", path, err)
+			} else {
+				res.LicenseFiles = append(res.LicenseFiles, license)
+			}
+		}
+
 		filePaths = append(filePaths, path)
 		return nil
 	}); err != nil {
@@ -92,6 +185,26 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 		return res, err
 	}
 
+	// 根据 .gitattributes 确定每个文件的 linguist 属性
+	attributes := loadGitAttributes(path, attributeFiles)
+	linguist := make(map[string]LinguistAttributes, len(filePaths))
+	filePaths = slices.DeleteFunc(filePaths, func(filePath string) bool {
+		relPath, err := filepath.Rel(path, filePath)
+		if err != nil {
+			return false
+		}
+
+		attrs := attributes.linguist(relPath)
+		if (attrs.Generated && options.ExcludeGenerated) ||
+			(attrs.Vendored && options.ExcludeVendored) ||
+			(attrs.Documentation && options.ExcludeDocumentation) {
+			PrintInfo("已跳过文件: %s (linguist)", filePath)
+			return true
+		}
+		linguist[filePath] = attrs
+		return false
+	})
+
 	// 计算文件数量
 	totalFiles := len(filePaths)
 	if totalFiles == 0 {
@@ -117,14 +230,44 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 		go func() {
 			defer wg.Done()
 			for path := range fileChan {
-				stats, err := AnalyzeFile(path)
+				attrs := linguist[path]
+				stats, err := analyzeFileAs(path, attrs.Language, options.FileAnalyzerOptions)
 				if err != nil {
 					PrintError("分析失败: %s (%v)", path, err)
 					continue
 				}
+				// 显式设置的 linguist-generated 优先于文件名和文件头的识别结果
+				if attrs.GeneratedSet {
+					stats.IsGenerated = attrs.Generated
+				}
+				stats.IsVendored = attrs.Vendored
+				stats.IsDocumentation = attrs.Documentation
+				if relPath, err := filepath.Rel(res.Path, path); err == nil {
+					stats.IsTest = isTestFile(relPath, options.TestPatterns)
+				}
+
+				// 通过内容识别出的生成代码和压缩文件需要在分析后排除
+				excluded := (stats.IsGenerated && options.ExcludeGenerated) || (stats.IsMinified && options.ExcludeMinified)
+				if excluded {
+					PrintInfo("已跳过文件: %s (生成或压缩的代码)", path)
+				}
 
 				mutex.Lock()
-				res.FileStats = append(res.FileStats, stats)
+				if stats.IsBinary {
+					// 二进制文件单独按扩展名统计
+					ext := strings.ToLower(filepath.Ext(path))
+					if _, exists := res.BinaryStats[ext]; !exists {
+						res.BinaryStats[ext] = &BinaryStats{}
+					}
+					res.BinaryStats[ext].TotalFiles++
+					res.BinaryStats[ext].TotalSize += stats.TotalSize
+				} else if stats.Encoding == EncodingUnknown {
+					PrintWarning("无法识别文件编码: %s", path)
+					res.UndecodableFiles = append(res.UndecodableFiles, path)
+				} else if !excluded {
+					res.EncodingStats[stats.Encoding]++
+					res.FileStats = append(res.FileStats, stats)
+				}
 				processedCnt++
 				// 更新进度条
 				_ = bar.Set(processedCnt)
@@ -142,16 +285,68 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 	// 等待所有工作完成
 	wg.Wait()
 
+	// 按内容哈希识别完全相同的文件，需要时只保留每组中的第一个文件
+	res.DuplicateFiles = findDuplicateFiles(res.FileStats)
+	if options.SkipDuplicateFiles && len(res.DuplicateFiles.Groups) > 0 {
+		res.DuplicateFiles.Skipped = true
+		copies := res.DuplicateFiles.copies()
+		res.FileStats = slices.DeleteFunc(res.FileStats, func(fs *FileStats) bool {
+			if copies[fs.Path] {
+				PrintInfo("已跳过文件: %s (重复文件)", fs.Path)
+				res.EncodingStats[fs.Encoding]--
+				return true
+			}
+			return false
+		})
+	}
+
+	// 检测文件之间及文件内部的重复代码
+	res.Duplication = findDuplicates(res.FileStats, options.DuplicateWindow)
+
+	// 通过 git blame 获取注释标记的作者和时间
+	if res.GitStats != nil {
+		blameMarkers(path, res.FileStats)
+	}
+
 	for _, fs := range res.FileStats {
 		// 汇总统计
 		res.Merge(fs.Stat)
 
-		// 语言统计
-		lang := fs.Language
-		if _, exists := res.LanguageStats[lang]; !exists {
-			res.LanguageStats[lang] = &LanguageStats{}
+		// 生成代码与手写代码分开统计
+		if fs.IsGenerated {
+			res.GeneratedStats.Merge(fs.Stat)
+		} else {
+			res.HandwrittenStats.Merge(fs.Stat)
+		}
+		if fs.IsMinified {
+			res.MinifiedStats.Merge(fs.Stat)
+		}
+
+		// 测试代码与非测试代码分开统计，同时按顶层目录统计
+		res.TestSplit.add(fs.Stat, fs.IsTest)
+		topDir := "."
+		if relPath, err := filepath.Rel(path, fs.Path); err == nil {
+			if dir, _, found := strings.Cut(filepath.ToSlash(relPath), "/"); found {
+				topDir = dir
+			}
+		}
+		if _, exists := res.TestDirs[topDir]; !exists {
+			res.TestDirs[topDir] = newTestSplit()
+		}
+		res.TestDirs[topDir].add(fs.Stat, fs.IsTest)
+
+		// 语言统计，包含内嵌语言的文件按区域分别计入对应语言
+		regions := fs.Regions
+		if len(regions) == 0 {
+			regions = map[string]*Stat{fs.Language: fs.Stat}
+		}
+		for lang, stat := range regions {
+			if _, exists := res.LanguageStats[lang]; !exists {
+				res.LanguageStats[lang] = newLanguageStats()
+			}
+			res.LanguageStats[lang].Merge(stat)
+			res.LanguageStats[lang].TestSplit.add(stat, fs.IsTest)
 		}
-		res.LanguageStats[lang].Merge(fs.Stat)
 
 		// 文件扩展名统计
 		ext := strings.ToLower(filepath.Ext(fs.Path))
@@ -159,11 +354,67 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 			res.ExtensionStats[ext] = &ExtensionStats{}
 		}
 		res.ExtensionStats[ext].Merge(fs.Stat)
+
+		// 许可证统计
+		if fs.License != "" {
+			res.LicenseStats[fs.License]++
+		} else if fs.MissingLicense && !fs.IsGenerated && !fs.IsVendored && !fs.IsMinified {
+			res.MissingLicense = append(res.MissingLicense, fs.Path)
+		}
+
+		// 注释标记按标记名和目录统计
+		if len(fs.Markers) > 0 {
+			dir, err := filepath.Rel(path, filepath.Dir(fs.Path))
+			if err != nil {
+				dir = filepath.Dir(fs.Path)
+			}
+			dir = filepath.ToSlash(dir)
+			for _, marker := range fs.Markers {
+				res.MarkerTags[marker.Tag]++
+				res.MarkerDirs[dir]++
+			}
+			res.Markers = append(res.Markers, fs.Markers...)
+		}
+
+		// Go 包统计，同一目录下的 xxx_test 外部测试包单独统计
+		if fs.Go != nil {
+			dir, err := filepath.Rel(path, fs.Go.Dir)
+			if err != nil {
+				dir = fs.Go.Dir
+			}
+			dir = filepath.ToSlash(dir)
+			key := dir + ":" + fs.Go.Name
+			if _, exists := res.GoPackages[key]; !exists {
+				res.GoPackages[key] = &GoPackageStats{GoStats: &GoStats{}, Name: fs.Go.Name, Dir: dir}
+			}
+			res.GoPackages[key].Files++
+			res.GoPackages[key].Merge(fs.Go.GoStats)
+			res.GoStats.Merge(fs.Go.GoStats)
+		}
 	}
 
+	sortLicenseFiles(res.LicenseFiles)
+	sort.Strings(res.MissingLicense)
+	sort.Slice(res.Markers, func(i, j int) bool {
+		if res.Markers[i].Path != res.Markers[j].Path {
+			return res.Markers[i].Path < res.Markers[j].Path
+		}
+		return res.Markers[i].Line < res.Markers[j].Line
+	})
+
 	res.CalculateAvg()
+	for _, stat := range []*Stat{res.GeneratedStats, res.HandwrittenStats, res.MinifiedStats} {
+		if stat.TotalFiles > 0 {
+			stat.CalculateAvg()
+		}
+	}
+	res.TestSplit.calculateAvg()
+	for _, split := range res.TestDirs {
+		split.calculateAvg()
+	}
 	for _, lang := range res.LanguageStats {
 		lang.CalculateAvg()
+		lang.TestSplit.calculateAvg()
 	}
 	for _, ext := range res.ExtensionStats {
 		ext.CalculateAvg()
@@ -171,6 +422,12 @@ func AnalyzeDirectory(path string, options DirectoryAnalyzerOptions) (*Directory
 	return res, nil
 }
 
+// 判断路径是否是普通文件
+func isRegularFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
 // 默认排除的目录
 var defaultExcludeDirs = []string{
 	".git", "node_modules", "vendor", "dist", "build",
@@ -179,17 +436,15 @@ var defaultExcludeDirs = []string{
 }
 
 // 默认排除的文件扩展名
+// 二进制文件通过内容识别并单独统计，这里只排除不需要统计的文本文件
 var defaultExcludeExt = []string{
-	".exe", ".dll", ".so", ".dylib", ".o", ".obj", ".a", ".lib",
-	".jar", ".war", ".ear", ".zip", ".tar", ".gz", ".rar",
-	".jpg", ".jpeg", ".png", ".gif", ".bmp", ".ico", ".svg",
-	".mp3", ".mp4", ".avi", ".mkv", ".wav", ".flac",
-	".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
-	".lock", ".sum", ".mod", ".toml", ".map",
+	".svg", ".lock", ".sum", ".mod", ".toml", ".map",
 }
 
 func DefaultOptions() DirectoryAnalyzerOptions {
 	return DirectoryAnalyzerOptions{
+		FileAnalyzerOptions: DefaultFileOptions(),
+
 		ExcludeDirs: defaultExcludeDirs,
 		ExcludeExt:  defaultExcludeExt,
 		MaxWorkers:  4,
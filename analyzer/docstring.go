@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 支持文档字符串的语言
+var docstringLanguages = []string{"Python", "Starlark"}
+
+var (
+	// 以字符串字面量开始的语句，允许 r、u 前缀，f-string 和 bytes 不是文档字符串
+	docstringStart = regexp.MustCompile(`^[rRuU]?("""|'''|"|')`)
+	// 函数或类定义的开始
+	definitionStart = regexp.MustCompile(`^(?:async\s+)?(?:def|class)\b`)
+	// 以冒号结束的行，允许行尾注释
+	blockOpener = regexp.MustCompile(`:\s*(?:#.*)?$`)
+)
+
+// docstringTracker 识别文档字符串，即模块、类或函数体中作为第一条语句的字符串字面量
+type docstringTracker struct {
+	expect bool // 下一条语句可能是文档字符串
+	inDoc  bool // 处于跨行的文档字符串中
+	header bool // 处于跨行的函数或类定义头中
+	depth  int  // 定义头中未闭合的括号数
+}
+
+// 创建文档字符串识别器，不支持文档字符串的语言返回 nil
+func newDocstringTracker(language string) *docstringTracker {
+	for _, lang := range docstringLanguages {
+		if lang == language {
+			// 文件开头可以是模块的文档字符串
+			return &docstringTracker{expect: true}
+		}
+	}
+	return nil
+}
+
+// 重置状态，用于重新进入同一种语言的新区域
+func (d *docstringTracker) reset() {
+	*d = docstringTracker{expect: true}
+}
+
+// 判断一行是否属于文档字符串，trimmed 为去除首尾空白的行，inString 表示行尾仍处于字符串中
+func (d *docstringTracker) isDoc(trimmed string, res lineResult, inString bool) bool {
+	if d.inDoc {
+		d.inDoc = inString
+		return true
+	}
+
+	// 只有注释的行不影响语句的顺序
+	if !res.hasCode {
+		return false
+	}
+
+	if d.expect && !d.header && docstringStart.MatchString(trimmed) {
+		d.expect = false
+		d.inDoc = inString
+		return true
+	}
+	d.expect = false
+
+	// 函数或类定义头可能跨越多行，在括号闭合后的冒号处结束
+	if !d.header && definitionStart.MatchString(trimmed) {
+		d.header = true
+		d.depth = 0
+	}
+	if d.header {
+		d.depth += strings.Count(trimmed, "(") + strings.Count(trimmed, "[") -
+			strings.Count(trimmed, ")") - strings.Count(trimmed, "]")
+		if d.depth <= 0 && !strings.HasSuffix(trimmed, `\`) {
+			d.header = false
+			// 单行定义（如 def f(): return 1）没有文档字符串
+			d.expect = blockOpener.MatchString(trimmed)
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// codeLine 是去除注释和空白后的一行代码
+type codeLine struct {
+	lineNo int    // 行号
+	hash   uint64 // 规范化后内容的哈希
+}
+
+// DuplicationStats 存储重复代码的统计
+type DuplicationStats struct {
+	Window          int           // 判定为重复所需的最少连续行数
+	CodeLines       int           // 参与比较的代码行数
+	DuplicatedLines int           // 属于重复代码块的行数
+	Ratio           float64       // 重复率: 重复行数/代码行数
+	CloneGroups     []*CloneGroup // 重复代码组，按行数和出现次数降序排列
+}
+
+// CloneGroup 表示内容相同的一组代码块
+type CloneGroup struct {
+	Lines     int             // 代码块的行数（规范化后的代码行）
+	Locations []CloneLocation // 代码块出现的位置
+}
+
+// CloneLocation 表示重复代码块的位置
+type CloneLocation struct {
+	Path      string // 文件路径
+	StartLine int    // 开始行号
+	EndLine   int    // 结束行号
+}
+
+// 规范化一行代码，只由标点组成的行（如 }、});）不参与比较，返回 false
+func normalizeLine(stripped string) (uint64, bool) {
+	if !strings.ContainsFunc(stripped, func(r rune) bool {
+		return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f
+	}) {
+		return 0, false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(stripped))
+	return h.Sum64(), true
+}
+
+// windowKey 是连续 window 行代码的哈希
+type windowKey uint64
+
+// windowRef 表示某个文件中从第 start 个代码行开始的窗口
+type windowRef struct {
+	file  int
+	start int
+}
+
+// 检测文件之间及文件内部的重复代码，window 为判定为重复所需的最少连续行数
+// 检测完成后会释放文件中保存的规范化代码行
+func findDuplicates(files []*FileStats, window int) *DuplicationStats {
+	res := &DuplicationStats{Window: window}
+	if window <= 0 {
+		return res
+	}
+
+	// 计算每个窗口的哈希，并按哈希分组
+	windows := make(map[windowKey][]windowRef)
+	for i, fs := range files {
+		res.CodeLines += len(fs.codeLines)
+		for start := 0; start+window <= len(fs.codeLines); start++ {
+			key := windowHash(fs.codeLines[start : start+window])
+			windows[key] = append(windows[key], windowRef{i, start})
+		}
+	}
+
+	// 为出现多次的窗口分配组号，groupOf[文件][窗口开始位置] 为组号，-1 表示没有重复
+	var groups [][]windowRef
+	groupOf := make([][]int, len(files))
+	for i, fs := range files {
+		groupOf[i] = make([]int, max(len(fs.codeLines)-window+1, 0))
+		for j := range groupOf[i] {
+			groupOf[i][j] = -1
+		}
+	}
+	for _, refs := range windows {
+		if len(refs) < 2 {
+			continue
+		}
+		for _, ref := range refs {
+			groupOf[ref.file][ref.start] = len(groups)
+		}
+		groups = append(groups, refs)
+	}
+
+	// 标记重复的代码行
+	duplicated := make([][]bool, len(files))
+	for i, fs := range files {
+		duplicated[i] = make([]bool, len(fs.codeLines))
+	}
+	for _, refs := range groups {
+		for _, ref := range refs {
+			for k := ref.start; k < ref.start+window; k++ {
+				duplicated[ref.file][k] = true
+			}
+		}
+	}
+	for i, fs := range files {
+		for _, dup := range duplicated[i] {
+			if dup {
+				fs.DuplicatedLines++
+			}
+		}
+		// 与整体重复率一样以参与比较的代码行数为分母
+		if n := len(fs.codeLines); n > 0 {
+			fs.DuplicationRatio = float64(fs.DuplicatedLines) / float64(n)
+		}
+		res.DuplicatedLines += fs.DuplicatedLines
+	}
+	if res.CodeLines > 0 {
+		res.Ratio = float64(res.DuplicatedLines) / float64(res.CodeLines)
+	}
+
+	// 将连续的重复窗口合并为最长的重复代码块
+	// 每组窗口的所有位置都在下一行延续同一组时，视为同一个代码块
+	next := func(refs []windowRef, offset int) int {
+		id := -1
+		for _, ref := range refs {
+			pos := ref.start + offset
+			if pos < 0 || pos >= len(groupOf[ref.file]) {
+				return -1
+			}
+			g := groupOf[ref.file][pos]
+			if g < 0 || (id >= 0 && g != id) || len(groups[g]) != len(refs) {
+				return -1
+			}
+			id = g
+		}
+		return id
+	}
+	for _, refs := range groups {
+		// 只从代码块的第一个窗口开始合并
+		if next(refs, -1) >= 0 {
+			continue
+		}
+		length := 1
+		for next(refs, length) >= 0 {
+			length++
+		}
+
+		group := &CloneGroup{Lines: window + length - 1}
+		for _, ref := range refs {
+			lines := files[ref.file].codeLines
+			group.Locations = append(group.Locations, CloneLocation{
+				Path:      files[ref.file].Path,
+				StartLine: lines[ref.start].lineNo,
+				EndLine:   lines[ref.start+group.Lines-1].lineNo,
+			})
+		}
+		sort.Slice(group.Locations, func(i, j int) bool {
+			if group.Locations[i].Path != group.Locations[j].Path {
+				return group.Locations[i].Path < group.Locations[j].Path
+			}
+			return group.Locations[i].StartLine < group.Locations[j].StartLine
+		})
+		res.CloneGroups = append(res.CloneGroups, group)
+	}
+	sort.Slice(res.CloneGroups, func(i, j int) bool {
+		a, b := res.CloneGroups[i], res.CloneGroups[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return len(a.Locations) > len(b.Locations)
+	})
+
+	for _, fs := range files {
+		fs.codeLines = nil
+	}
+	return res
+}
+
+// 计算连续多行代码的哈希
+func windowHash(lines []codeLine) windowKey {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, line := range lines {
+		for i := range buf {
+			buf[i] = byte(line.hash >> (8 * i))
+		}
+		h.Write(buf[:])
+	}
+	return windowKey(h.Sum64())
+}
+
+// DuplicateFileStats 存储内容完全相同的文件的统计
+type DuplicateFileStats struct {
+	Groups      []*DuplicateFileGroup // 重复文件组，按浪费的字节数降序排列
+	Files       int                   // 多余的副本数（每组保留一个文件）
+	WastedLines int                   // 多余副本的总行数
+	WastedBytes int64                 // 多余副本的总字节数
+	Skipped     bool                  // 多余的副本是否已从统计中排除
+}
+
+// DuplicateFileGroup 表示内容完全相同的一组文件
+type DuplicateFileGroup struct {
+	Hash        string   // 文件内容的 SHA-256
+	Paths       []string // 文件路径，按路径排序，第一个文件视为原件
+	Lines       int      // 每个文件的行数
+	Size        int64    // 每个文件的字节数
+	WastedLines int      // 多余副本的行数
+	WastedBytes int64    // 多余副本的字节数
+}
+
+// 按内容哈希对文件分组，返回包含多个文件的组
+func findDuplicateFiles(files []*FileStats) *DuplicateFileStats {
+	res := &DuplicateFileStats{}
+	byHash := make(map[string][]*FileStats)
+	for _, fs := range files {
+		if fs.Hash != "" {
+			byHash[fs.Hash] = append(byHash[fs.Hash], fs)
+		}
+	}
+
+	for hash, same := range byHash {
+		if len(same) < 2 {
+			continue
+		}
+		group := &DuplicateFileGroup{
+			Hash:  hash,
+			Lines: same[0].TotalLines,
+			Size:  same[0].TotalSize,
+		}
+		for _, fs := range same {
+			group.Paths = append(group.Paths, fs.Path)
+		}
+		sort.Strings(group.Paths)
+		copies := len(same) - 1
+		group.WastedLines = copies * group.Lines
+		group.WastedBytes = int64(copies) * group.Size
+
+		res.Groups = append(res.Groups, group)
+		res.Files += copies
+		res.WastedLines += group.WastedLines
+		res.WastedBytes += group.WastedBytes
+	}
+	sort.Slice(res.Groups, func(i, j int) bool {
+		a, b := res.Groups[i], res.Groups[j]
+		if a.WastedBytes != b.WastedBytes {
+			return a.WastedBytes > b.WastedBytes
+		}
+		return a.Paths[0] < b.Paths[0]
+	})
+	return res
+}
+
+// 获取重复文件组中多余的副本，即每组除第一个文件外的文件
+func (d *DuplicateFileStats) copies() map[string]bool {
+	copies := make(map[string]bool, d.Files)
+	for _, group := range d.Groups {
+		for _, path := range group.Paths[1:] {
+			copies[path] = true
+		}
+	}
+	return copies
+}
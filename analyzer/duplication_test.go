@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 根据哈希序列创建规范化代码行，行号从 firstLine 开始连续编号
+func testCodeLines(firstLine int, hashes ...uint64) []codeLine {
+	lines := make([]codeLine, len(hashes))
+	for i, hash := range hashes {
+		lines[i] = codeLine{lineNo: firstLine + i, hash: hash}
+	}
+	return lines
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		window     int
+		files      map[string][]codeLine
+		wantGroups []*CloneGroup
+		wantDup    map[string]int
+		wantRatio  float64
+	}{
+		{
+			name:   "overlapping windows merge into one block",
+			window: 3,
+			files: map[string][]codeLine{
+				"a.go": testCodeLines(1, 100, 1, 2, 3, 4, 5, 101),
+				"b.go": testCodeLines(10, 1, 2, 3, 4, 5, 200),
+			},
+			wantGroups: []*CloneGroup{{Lines: 5, Locations: []CloneLocation{
+				{Path: "a.go", StartLine: 2, EndLine: 6},
+				{Path: "b.go", StartLine: 10, EndLine: 14},
+			}}},
+			wantDup:   map[string]int{"a.go": 5, "b.go": 5},
+			wantRatio: 10.0 / 13.0,
+		},
+		{
+			name:   "block repeated inside one file",
+			window: 2,
+			files: map[string][]codeLine{
+				"a.go": testCodeLines(1, 1, 2, 9, 1, 2),
+			},
+			wantGroups: []*CloneGroup{{Lines: 2, Locations: []CloneLocation{
+				{Path: "a.go", StartLine: 1, EndLine: 2},
+				{Path: "a.go", StartLine: 4, EndLine: 5},
+			}}},
+			wantDup:   map[string]int{"a.go": 4},
+			wantRatio: 4.0 / 5.0,
+		},
+		{
+			name:   "shorter than window",
+			window: 4,
+			files: map[string][]codeLine{
+				"a.go": testCodeLines(1, 1, 2, 3),
+				"b.go": testCodeLines(1, 1, 2, 3),
+			},
+			wantDup: map[string]int{"a.go": 0, "b.go": 0},
+		},
+		{
+			name:   "disabled",
+			window: 0,
+			files: map[string][]codeLine{
+				"a.go": testCodeLines(1, 1, 2, 3),
+				"b.go": testCodeLines(1, 1, 2, 3),
+			},
+			wantDup: map[string]int{"a.go": 0, "b.go": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []*FileStats
+			for _, path := range []string{"a.go", "b.go"} {
+				if lines, ok := tt.files[path]; ok {
+					files = append(files, &FileStats{Stat: &Stat{CodeLines: len(lines)}, Path: path, codeLines: lines})
+				}
+			}
+
+			res := findDuplicates(files, tt.window)
+			if !reflect.DeepEqual(res.CloneGroups, tt.wantGroups) {
+				t.Errorf("CloneGroups = %+v, want %+v", res.CloneGroups, tt.wantGroups)
+			}
+			if res.Ratio != tt.wantRatio {
+				t.Errorf("Ratio = %v, want %v", res.Ratio, tt.wantRatio)
+			}
+			for _, fs := range files {
+				if fs.DuplicatedLines != tt.wantDup[fs.Path] {
+					t.Errorf("%s: DuplicatedLines = %d, want %d", fs.Path, fs.DuplicatedLines, tt.wantDup[fs.Path])
+				}
+				if fs.DuplicationRatio > 1 {
+					t.Errorf("%s: DuplicationRatio = %v, want <= 1", fs.Path, fs.DuplicationRatio)
+				}
+			}
+		})
+	}
+}
+
+func TestFindDuplicatesRatioUsesComparedLines(t *testing.T) {
+	// 文档字符串等不计入 CodeLines 的行也参与比较，文件重复率应与整体重复率使用相同的分母
+	lines := testCodeLines(1, 1, 2, 3)
+	a := &FileStats{Stat: &Stat{CodeLines: 1}, Path: "a.py", codeLines: lines}
+	b := &FileStats{Stat: &Stat{CodeLines: 1}, Path: "b.py", codeLines: testCodeLines(1, 1, 2, 3)}
+
+	res := findDuplicates([]*FileStats{a, b}, 3)
+	if a.DuplicationRatio != 1 || res.Ratio != 1 {
+		t.Errorf("DuplicationRatio = %v, Ratio = %v, want 1", a.DuplicationRatio, res.Ratio)
+	}
+	if a.codeLines != nil {
+		t.Error("codeLines should be released after detection")
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// regionSplitter 将文件按内嵌语言划分为不同区域
+type regionSplitter interface {
+	// 返回该行所属的语言，需要按顺序逐行调用
+	languageOf(line string) string
+}
+
+// 创建宿主语言对应的区域划分器，不支持内嵌语言时返回 nil
+func newRegionSplitter(language string) regionSplitter {
+	switch language {
+	case "HTML", "Vue", "Svelte":
+		return &tagSplitter{host: language}
+	case "Markdown":
+		return &fenceSplitter{host: language}
+	}
+	return nil
+}
+
+var (
+	openTagPattern  = regexp.MustCompile(`(?i)<(script|style)\b`)
+	closeTagPattern = regexp.MustCompile(`(?i)</(script|style)\s*>`)
+	attrPattern     = regexp.MustCompile(`(?i)\b(lang|type)\s*=\s*["']?([\w/+.-]+)`)
+)
+
+// tagSplitter 识别 HTML、Vue、Svelte 中的 <script> 和 <style> 区域
+// 标签所在的行属于宿主语言，标签之间的行属于内嵌语言
+type tagSplitter struct {
+	host     string // 宿主语言
+	embedded string // 当前内嵌语言，为空表示处于宿主语言中
+	tag      string // 当前区域的标签名
+	pending  string // 尚未闭合的开始标签内容（开始标签跨行时）
+}
+
+func (s *tagSplitter) languageOf(line string) string {
+	// 开始标签跨行，等待标签闭合
+	if s.pending != "" {
+		s.pending += " " + line
+		if strings.Contains(line, ">") {
+			s.enter(s.pending)
+		}
+		return s.host
+	}
+
+	// 处于内嵌区域中，遇到结束标签时回到宿主语言
+	if s.embedded != "" {
+		if m := closeTagPattern.FindStringSubmatch(line); m != nil && strings.EqualFold(m[1], s.tag) {
+			s.embedded = ""
+			return s.host
+		}
+		return s.embedded
+	}
+
+	loc := openTagPattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return s.host
+	}
+
+	// 开始标签与结束标签在同一行时视为宿主语言
+	rest := line[loc[0]:]
+	if closeTagPattern.MatchString(rest) {
+		return s.host
+	}
+
+	s.tag = strings.ToLower(line[loc[2]:loc[3]])
+	if strings.Contains(rest, ">") {
+		s.enter(rest)
+	} else {
+		s.pending = rest
+	}
+	return s.host
+}
+
+// 根据开始标签的属性进入内嵌区域
+func (s *tagSplitter) enter(tag string) {
+	s.pending = ""
+
+	attrs := make(map[string]string)
+	for _, m := range attrPattern.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(m[1])] = strings.ToLower(m[2])
+	}
+
+	if s.tag == "style" {
+		s.embedded = "CSS"
+		if lang := LookupLanguage(attrs["lang"]); lang != "" {
+			s.embedded = lang
+		}
+		return
+	}
+
+	s.embedded = "JavaScript"
+	switch {
+	case attrs["lang"] != "":
+		if lang := LookupLanguage(attrs["lang"]); lang != "" {
+			s.embedded = lang
+		}
+	case strings.Contains(attrs["type"], "typescript"):
+		s.embedded = "TypeScript"
+	case strings.Contains(attrs["type"], "json"):
+		s.embedded = "JSON"
+	case strings.Contains(attrs["type"], "template"), strings.Contains(attrs["type"], "html"):
+		// 模板脚本仍属于宿主语言
+		s.embedded = s.host
+	}
+}
+
+// fenceSplitter 识别 Markdown 中的围栏代码块，代码块按标注的语言统计
+// 围栏所在的行以及未标注或无法识别语言的代码块属于宿主语言
+type fenceSplitter struct {
+	host     string // 宿主语言
+	fence    string // 当前代码块的围栏，为空表示不在代码块中
+	embedded string // 当前代码块的语言
+}
+
+func (s *fenceSplitter) languageOf(line string) string {
+	trimmed := strings.TrimSpace(line)
+
+	if s.fence != "" {
+		// 结束围栏至少与开始围栏等长，且后面不能有其他内容
+		if strings.HasPrefix(trimmed, s.fence) && strings.Trim(trimmed, s.fence[:1]) == "" {
+			s.fence = ""
+			return s.host
+		}
+		return s.embedded
+	}
+
+	// 超过三个空格的缩进是缩进代码块，不是围栏
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return s.host
+	}
+	for _, c := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if n < 3 {
+			continue
+		}
+
+		s.fence = strings.Repeat(c, n)
+		s.embedded = s.host
+		if info := strings.Fields(trimmed[n:]); len(info) > 0 {
+			// 信息字符串形如 ```go 或 ```{.python}
+			if lang := LookupLanguage(strings.Trim(info[0], "{}.")); lang != "" {
+				s.embedded = lang
+			}
+		}
+		break
+	}
+	return s.host
+}
@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// 支持识别的文件编码
+const (
+	EncodingUTF8    = "UTF-8"
+	EncodingUTF8BOM = "UTF-8 BOM"
+	EncodingUTF16LE = "UTF-16LE"
+	EncodingUTF16BE = "UTF-16BE"
+	EncodingGBK     = "GBK"
+	EncodingUnknown = "Unknown" // 无法解码
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// 无 BOM 的 UTF-16 判断阈值：一半位置上 NUL 字节的比例
+const utf16NulRate = 0.4
+
+// 识别文件编码并转换为 UTF-8，无法解码时返回 EncodingUnknown
+func decodeText(content []byte) ([]byte, string) {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return content[len(bomUTF8):], EncodingUTF8BOM
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return transcode(content, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), EncodingUTF16LE)
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return transcode(content, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM), EncodingUTF16BE)
+	}
+
+	switch detectUTF16(content) {
+	case EncodingUTF16LE:
+		return transcode(content, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), EncodingUTF16LE)
+	case EncodingUTF16BE:
+		return transcode(content, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), EncodingUTF16BE)
+	}
+
+	if utf8.Valid(content) {
+		return content, EncodingUTF8
+	}
+	if decoded, ok := decodeGBK(content); ok {
+		return decoded, EncodingGBK
+	}
+	return content, EncodingUnknown
+}
+
+// 使用指定编码转换为 UTF-8
+func transcode(content []byte, enc encoding.Encoding, name string) ([]byte, string) {
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return content, EncodingUnknown
+	}
+	return decoded, name
+}
+
+// 根据 NUL 字节的位置识别没有 BOM 的 UTF-16 文本
+func detectUTF16(sample []byte) string {
+	sample = limitBytes(sample)
+	if len(sample) < 4 {
+		return ""
+	}
+
+	var even, odd int
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			even++
+		}
+		if sample[i+1] == 0 {
+			odd++
+		}
+	}
+
+	pairs := float64(len(sample) / 2)
+	switch {
+	case float64(odd)/pairs > utf16NulRate && even == 0:
+		return EncodingUTF16LE
+	case float64(even)/pairs > utf16NulRate && odd == 0:
+		return EncodingUTF16BE
+	}
+	return ""
+}
+
+// 尝试按 GBK 解码，存在无效字节序列时失败
+func decodeGBK(content []byte) ([]byte, bool) {
+	decoded, err := simplifiedchinese.GBK.NewDecoder().Bytes(content)
+	if err != nil {
+		return nil, false
+	}
+
+	// 解码器会将无效序列替换为 U+FFFD，原文中不存在的替换字符说明解码失败
+	if bytes.Count(decoded, []byte(string(utf8.RuneError))) > bytes.Count(content, []byte(string(utf8.RuneError))) {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// 判断采样内容是否是 UTF-16 文本（有 BOM 或符合 ASCII 字符交替出现零字节的特征）
+func isUTF16Text(sample []byte) bool {
+	return bytes.HasPrefix(sample, bomUTF16LE) || bytes.HasPrefix(sample, bomUTF16BE) || detectUTF16(sample) != ""
+}
+
+// 判断采样内容是否是非 UTF-8 编码的文本，用于二进制检测时排除 UTF-16 和 GBK 文本
+func isEncodedText(sample []byte) bool {
+	if isUTF16Text(sample) {
+		return true
+	}
+
+	// 去掉可能被截断的末尾双字节字符
+	if n := len(sample); n > 0 && sample[n-1] >= 0x81 {
+		sample = sample[:n-1]
+	}
+	// ASCII 控制字符也是有效的 GBK，解码后仍需满足文本的特征
+	decoded, ok := decodeGBK(sample)
+	return ok && suspiciousRatio(decoded) <= binaryThreshold
+}
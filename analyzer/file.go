@@ -2,23 +2,94 @@ package analyzer
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
-	"slices"
+	"sort"
 	"strings"
 )
 
+// MixedLinePolicy 决定同时包含代码与注释的行如何计数
+type MixedLinePolicy string
+
+const (
+	MixedAsCode    MixedLinePolicy = "code"    // 只计为代码行（与 cloc 一致）
+	MixedAsComment MixedLinePolicy = "comment" // 只计为注释行
+	MixedAsBoth    MixedLinePolicy = "both"    // 同时计为代码行和注释行
+)
+
+// ParseMixedLinePolicy 解析混合行计数方式
+func ParseMixedLinePolicy(s string) (MixedLinePolicy, error) {
+	switch policy := MixedLinePolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case MixedAsCode, MixedAsComment, MixedAsBoth:
+		return policy, nil
+	}
+	return "", fmt.Errorf("未知的混合行计数方式: %s (可选: code, comment, both)", s)
+}
+
+// FileAnalyzerOptions 配置文件分析器的选项
+type FileAnalyzerOptions struct {
+	MixedLinePolicy   MixedLinePolicy // 混合行的计数方式
+	GeneratedPatterns []string        // 额外的生成代码文件名模式（filepath.Match 语法，如 *_gen.go）
+	LongLineThreshold int             // 超长行的字符数阈值，不大于 0 时不统计
+	DuplicateWindow   int             // 判定为重复代码所需的最少连续行数，不大于 0 时不检测
+	CommentMarkers    []string        // 注释中需要识别的标记（如 TODO、FIXME），为空时不识别
+}
+
+// DefaultFileOptions 返回默认的文件分析选项
+func DefaultFileOptions() FileAnalyzerOptions {
+	return FileAnalyzerOptions{
+		MixedLinePolicy:   MixedAsCode,
+		LongLineThreshold: 120,
+		DuplicateWindow:   6,
+		CommentMarkers:    defaultCommentMarkers,
+	}
+}
+
 type FileStats struct {
 	*Stat
 
 	Path     string // 文件路径
 	Language string // 语言
+	Encoding string // 文件编码，无法解码时为 Unknown
+	Hash     string // 文件内容的 SHA-256，用于识别内容相同的文件
+
+	// 内嵌语言的统计（如 HTML 中的 JavaScript、Markdown 中的代码块），为空表示整个文件属于同一种语言
+	Regions map[string]*Stat
+
+	Notebook *NotebookStats  // Jupyter Notebook 的单元格统计，其他文件为空
+	Go       *GoPackageStats // Go 文件的结构统计，其他文件或解析失败时为空
+
+	Functions []*FunctionStats // 每个函数的统计，按定义顺序排列
+	Markers   []*CommentMarker // 注释中的标记（如 TODO、FIXME），按行号排列
+
+	License        string // 文件头声明的许可证（SPDX 标识符），没有许可证头时为空
+	MissingLicense bool   // 是否是缺少许可证头的源代码文件
+
+	DuplicatedLines  int        // 属于重复代码块的行数，分析目录时计算
+	DuplicationRatio float64    // 重复率: 重复行数/参与比较的代码行数
+	codeLines        []codeLine // 规范化后的代码行，检测重复代码后释放
+
+	IsBinary        bool // 是否是二进制文件，二进制文件不统计行数
+	IsMinified      bool // 是否是压缩或打包后的文件
+	IsGenerated     bool // 是否是生成的代码
+	IsVendored      bool // 是否是第三方代码
+	IsDocumentation bool // 是否是文档
+	IsTest          bool // 是否是测试代码，分析目录时根据路径识别
 }
 
-func AnalyzeFile(path string) (*FileStats, error) {
+func AnalyzeFile(path string, options FileAnalyzerOptions) (*FileStats, error) {
+	return analyzeFileAs(path, "", options)
+}
+
+// 分析文件，language 不为空时跳过语言识别
+func analyzeFileAs(path, language string, options FileAnalyzerOptions) (*FileStats, error) {
 	res := &FileStats{
-		Stat:     &Stat{TotalFiles: 1},
-		Path:     path,
-		Language: GetLanguageByExt(path),
+		Stat: &Stat{TotalFiles: 1},
+		Path: path,
 	}
 
 	// 分析文件大小
@@ -26,11 +97,64 @@ func AnalyzeFile(path string) (*FileStats, error) {
 		return res, err
 	}
 
-	// 分析文件内容
-	if err := res.analyzeFile(path); err != nil {
+	content, binary, err := readTextFile(path)
+	if err != nil {
+		PrintError("无法读取文件: %s (%v)", path, err)
 		return res, err
 	}
 
+	// 二进制文件只统计大小
+	if binary {
+		res.IsBinary = true
+		res.Language = "Binary"
+		return res, nil
+	}
+
+	sum := sha256.Sum256(content)
+	res.Hash = hex.EncodeToString(sum[:])
+
+	// 识别编码并统一转换为 UTF-8，无法解码的文件不统计行数
+	content, res.Encoding = decodeText(content)
+	if res.Encoding == EncodingUnknown {
+		return res, nil
+	}
+
+	// 根据文件名和内容识别语言
+	res.Language = language
+	if res.Language == "" {
+		res.Language = DetectLanguage(path, content)
+	}
+
+	// 根据文件名和文件头标记识别生成的代码
+	res.IsGenerated = isGeneratedFile(path, content, options.GeneratedPatterns)
+
+	// 分析文件内容，Jupyter Notebook 需要解析 JSON 后按单元格统计
+	analyze := res.analyzeFile
+	if res.Language == NotebookLanguage {
+		analyze = res.analyzeNotebook
+	}
+	if err := analyze(content, options); err != nil {
+		return res, err
+	}
+
+	// Go 文件额外通过语法树分析代码结构
+	if res.Language == "Go" {
+		res.Go, res.Functions = analyzeGoSource(path, content)
+		for _, fn := range res.Functions {
+			res.addFunction(fn.Lines)
+		}
+		res.CalculateAvg()
+	}
+
+	// 从文件开头的注释块中识别许可证，标记语言和数据格式不检查
+	if needsLicenseHeader(res.Language) {
+		res.License = detectLicenseHeader(string(content), res.Language)
+		res.MissingLicense = res.License == ""
+	}
+
+	// 根据文件名、打包标记和行长度识别压缩文件
+	res.IsMinified = res.isMinified(content)
+
 	return res, nil
 }
 
@@ -44,93 +168,81 @@ func (f *FileStats) analyzeSize(path string) error {
 	return nil
 }
 
-func (f *FileStats) analyzeFile(path string) error {
-	// 获取当前语言的注释标记
-	commentStyle, hasCommentStyle := CommentPatterns[f.Language]
-
-	file, err := os.Open(path)
-	if err != nil {
-		PrintError("无法打开文件: %s (%v)", path, err)
-		return err
-	}
-	defer file.Close()
-
-	var inMultilineComment bool   // 是否在多行注释中
-	var multilineEndMarker string // 多行注释结束标记
-	for scanner := bufio.NewScanner(file); scanner.Scan(); {
-		line := scanner.Text()
-		trimmedLine := strings.TrimSpace(line)
-
-		lineLength := len(line)
-		f.TotalChars += lineLength
-
-		// 统计行数
-		f.TotalLines++
-
-		// 统计空白行数
-		if trimmedLine == "" {
-			f.BlankLines++
-			continue
+func (f *FileStats) analyzeFile(content []byte, options FileAnalyzerOptions) error {
+	// HTML、Vue、Markdown 等文件按内嵌语言划分区域，其余文件整体属于同一种语言
+	splitter := newRegionSplitter(f.Language)
+	counters := make(map[string]*lineCounter)
+	currentLang := ""
+	lineNo := 0
+
+	// 使用 bufio.Reader 逐行读取，不受 bufio.Scanner 单行 64KB 的限制
+	reader := bufio.NewReader(bytes.NewReader(content))
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("读取文件失败: %v", err)
 		}
-
-		// 如果找不到当前语言的注释样式，则全部视为代码行
-		if !hasCommentStyle {
-			f.CodeLines++
-			continue
+		if line == "" && err == io.EOF {
+			break
 		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
 
-		// 检查是否在多行注释中
-		if inMultilineComment {
-			f.CommentLines++
-			// 检查多行注释结束
-			for _, endMarker := range commentStyle.MultiEnd {
-				if strings.Contains(trimmedLine, endMarker) && endMarker == multilineEndMarker {
-					inMultilineComment = false
-					multilineEndMarker = ""
-					break
-				}
-			}
-			continue
+		lang := f.Language
+		if splitter != nil {
+			lang = splitter.languageOf(line)
 		}
 
-		// 检查是否是单行注释
-		if slices.ContainsFunc(commentStyle.SingleLine, func(marker string) bool {
-			return strings.TrimSpace(strings.Split(trimmedLine, marker)[0]) == ""
-		}) {
-			f.CommentLines++
-			continue
+		counter, exists := counters[lang]
+		if !exists {
+			counter = newLineCounter(lang)
+			counters[lang] = counter
+		} else if lang != currentLang && lang != f.Language {
+			// 每个内嵌区域都是独立的代码块
+			counter.reset()
 		}
+		currentLang = lang
+		lineNo++
+		counter.count(line, lineNo, options)
+	}
 
-		// 检查多行注释开始
-		var isComment bool
-		for i, startMarker := range commentStyle.MultiStart {
-			if strings.Contains(trimmedLine, startMarker) {
-				f.CommentLines++
-				inMultilineComment = true
-
-				// 确保有匹配的结束标记
-				if i < len(commentStyle.MultiEnd) {
-					multilineEndMarker = commentStyle.MultiEnd[i]
-				}
-
-				// 检查多行注释是否在同一行结束
-				if strings.Contains(trimmedLine, multilineEndMarker) {
-					inMultilineComment = false
-					multilineEndMarker = ""
-					break
-				}
-
-				isComment = true
-				break
-			}
-		}
+	f.mergeCounters(counters)
+	return nil
+}
 
-		// 如果既不是单行注释也不是多行注释，则视为代码行
-		if !isComment {
-			f.CodeLines++
+// 汇总各语言计数器的统计
+// 包含多种语言时记录各区域的统计，宿主语言承担文件数和文件大小
+func (f *FileStats) mergeCounters(counters map[string]*lineCounter) {
+	for _, counter := range counters {
+		counter.finishFunctions()
+		f.Merge(counter.stat)
+		f.Functions = append(f.Functions, counter.functions...)
+		f.codeLines = append(f.codeLines, counter.codeLines...)
+		for _, marker := range counter.markers {
+			marker.Path = f.Path
+			f.Markers = append(f.Markers, marker)
+		}
+	}
+	sort.Slice(f.Functions, func(i, j int) bool {
+		return f.Functions[i].Line < f.Functions[j].Line
+	})
+	sort.Slice(f.Markers, func(i, j int) bool {
+		return f.Markers[i].Line < f.Markers[j].Line
+	})
+	sort.Slice(f.codeLines, func(i, j int) bool {
+		return f.codeLines[i].lineNo < f.codeLines[j].lineNo
+	})
+
+	if len(counters) > 1 || (len(counters) == 1 && counters[f.Language] == nil) {
+		f.Regions = make(map[string]*Stat, len(counters)+1)
+		for lang, counter := range counters {
+			f.Regions[lang] = counter.stat
+		}
+		if f.Regions[f.Language] == nil {
+			f.Regions[f.Language] = &Stat{}
 		}
+		f.Regions[f.Language].TotalFiles = 1
+		f.Regions[f.Language].TotalSize = f.TotalSize
 	}
 
 	f.CalculateAvg()
-	return nil
 }
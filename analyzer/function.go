@@ -0,0 +1,338 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// 函数长度分布的统计阈值（行）
+var functionLengthThresholds = []int{50, 100, 200}
+
+// functionTracker 根据逐行输入识别函数边界
+type functionTracker interface {
+	// 处理一行，res 为词法分析结果，inString 表示行首处于跨行字符串中
+	line(lineNo int, line string, res lineResult, inString bool)
+	// 结束所有未结束的函数，返回识别出的函数并清空状态
+	finish(lineNo int) []*FunctionStats
+}
+
+// 使用花括号界定函数体的语言
+var braceFunctionLanguages = []string{
+	"Java", "JavaScript", "TypeScript", "React JSX", "React TSX",
+	"C", "C++", "C/C++ Header", "C++ Header", "C#", "Objective-C",
+	"Rust", "Swift", "Kotlin", "Scala", "Dart", "PHP", "Groovy",
+}
+
+// 可以用 = 连接函数头与花括号函数体的语言，如 Scala 的 def f(x: Int): Int = {
+var expressionBodyLanguages = []string{"Scala", "Kotlin"}
+
+// 使用缩进界定函数体的语言
+var indentFunctionLanguages = []string{"Python", "Starlark"}
+
+// 创建语言对应的函数识别器，不支持的语言返回 nil
+// Go 文件通过语法树识别函数，不使用这里的启发式规则
+func newFunctionTracker(language string) functionTracker {
+	switch {
+	case slices.Contains(braceFunctionLanguages, language):
+		return &braceFunctionTracker{exprBody: slices.Contains(expressionBodyLanguages, language)}
+	case slices.Contains(indentFunctionLanguages, language):
+		return &indentFunctionTracker{}
+	}
+	return nil
+}
+
+// 记录一个函数的长度
+func (s *Stat) addFunction(lines int) {
+	s.FunctionCount++
+	s.FunctionLines += lines
+	s.MaxFunctionLength = max(s.MaxFunctionLength, lines)
+	if lines > functionLengthThresholds[0] {
+		s.FunctionsOver50++
+	}
+	if lines > functionLengthThresholds[1] {
+		s.FunctionsOver100++
+	}
+	if lines > functionLengthThresholds[2] {
+		s.FunctionsOver200++
+	}
+}
+
+var (
+	// 函数调用或定义形式的 name(，不匹配成员访问 obj.name(
+	callPattern = regexp.MustCompile(`(?:^|[^\w$.])([A-Za-z_$~][\w$]*)\s*(?:<[^()]*>)?\s*\(`)
+	// 赋值给变量或属性的箭头函数，如 const f = (a) => {
+	arrowPattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*[=:]\s*(?:async\s+)?(?:\([^()]*\)|[A-Za-z_$][\w$]*)\s*(?::[^=]+)?=>\s*\{`)
+	// Python 函数定义
+	defPattern = regexp.MustCompile(`^(?:async\s+)?def\s+([A-Za-z_]\w*)`)
+	// Rust、C#、Swift 的泛型约束子句
+	wherePattern = regexp.MustCompile(`\bwhere\b`)
+)
+
+// 不能作为函数名的关键字，出现在行首时该行也不是函数定义
+var nonFunctionKeywords = []string{
+	"if", "else", "elif", "for", "foreach", "while", "do", "switch", "case", "catch", "try",
+	"return", "new", "throw", "await", "yield", "delete", "typeof", "sizeof", "synchronized",
+	"using", "lock", "with", "when", "match", "guard", "unless", "until", "assert", "defer",
+	"class", "struct", "interface", "enum", "object", "record", "namespace", "extends", "implements",
+}
+
+// 参数列表之后、函数体之前的续行可以使用的开头单词，如 Java 的 throws、C++ 的 const
+var headerContinuationWords = []string{
+	"throws", "rethrows", "where", "async", "const", "noexcept", "override", "final", "requires",
+}
+
+// 函数头最多跨越的行数，超过后放弃识别
+const maxHeaderLines = 10
+
+// openFunction 是尚未结束的函数
+type openFunction struct {
+	fn    *FunctionStats
+	level int // 函数体的开始位置，花括号语言为左花括号外的深度，缩进语言为 def 的缩进
+	last  int // 函数体最后一个非空行的行号
+}
+
+// braceFunctionTracker 通过函数头和花括号深度识别 C 系语言的函数
+type braceFunctionTracker struct {
+	exprBody bool // 是否允许 = { 形式的函数体
+	depth    int  // 当前花括号深度
+
+	header      *FunctionStats // 正在识别的函数头
+	headerLines int            // 函数头已读取的行数
+	parens      int            // 函数头中未闭合的括号数
+	awaitBrace  bool           // 参数列表已结束，等待之后的行中出现左花括号
+	where       bool           // 函数头中出现了 where 子句，之后的约束行都属于函数头
+
+	open      []openFunction
+	functions []*FunctionStats
+}
+
+func (t *braceFunctionTracker) line(lineNo int, _ string, res lineResult, _ bool) {
+	code := res.code
+	brace := -1 // 函数体开始的左花括号在本行中的位置
+
+	switch {
+	case t.awaitBrace:
+		// 参数列表与左花括号之间的行，如独立的左花括号（Allman 风格）、throws 子句、返回类型和 where 子句
+		t.headerLines++
+		head, _, found := strings.Cut(code, "{")
+		if t.isHeaderContinuation(head) {
+			if found {
+				t.awaitBrace = false
+				brace = len(head)
+				break
+			}
+			if t.headerLines < maxHeaderLines {
+				break
+			}
+		}
+		t.awaitBrace = false
+		t.header = nil
+		brace = t.detect(lineNo, code)
+
+	case t.header != nil:
+		brace = t.continueHeader(code, 0)
+
+	default:
+		brace = t.detect(lineNo, code)
+	}
+
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			if i == brace && t.header != nil {
+				t.open = append(t.open, openFunction{fn: t.header, level: t.depth})
+				t.header = nil
+			}
+			t.depth++
+		case '}':
+			t.depth--
+			if n := len(t.open); n > 0 && t.open[n-1].level == t.depth {
+				t.close(t.open[n-1].fn, lineNo)
+				t.open = t.open[:n-1]
+			}
+		}
+	}
+}
+
+// 识别函数头的开始，返回函数体左花括号在本行中的位置，没有时返回 -1
+func (t *braceFunctionTracker) detect(lineNo int, code string) int {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" || slices.Contains(nonFunctionKeywords, firstWord(trimmed)) {
+		return -1
+	}
+
+	if loc := arrowPattern.FindStringSubmatchIndex(code); loc != nil {
+		t.header = &FunctionStats{Name: code[loc[2]:loc[3]], Line: lineNo}
+		return loc[1] - 1
+	}
+
+	loc := callPattern.FindStringSubmatchIndex(code)
+	if loc == nil {
+		return -1
+	}
+	name := code[loc[2]:loc[3]]
+	if slices.Contains(nonFunctionKeywords, name) {
+		return -1
+	}
+	if name == "function" {
+		name = "(anonymous)"
+	}
+
+	t.header = &FunctionStats{Name: name, Line: lineNo}
+	t.headerLines = 0
+	t.parens = 0
+	t.where = false
+	return t.continueHeader(code, loc[1]-1)
+}
+
+// 继续读取函数头，从 start 开始统计括号，返回函数体左花括号在本行中的位置
+func (t *braceFunctionTracker) continueHeader(code string, start int) int {
+	t.headerLines++
+	for i := start; i < len(code); i++ {
+		switch code[i] {
+		case '(':
+			t.parens++
+		case ')':
+			t.parens--
+		case ';':
+			// 函数声明或调用语句
+			t.header = nil
+			return -1
+		}
+		if t.parens > 0 || code[i] != ')' {
+			continue
+		}
+
+		// 参数列表结束，检查之后的内容是否是函数体的开始
+		tail := code[i+1:]
+		brace := strings.IndexByte(tail, '{')
+		if !isFunctionTail(tail, brace, t.exprBody) {
+			t.header = nil
+			return -1
+		}
+		if brace < 0 {
+			t.awaitBrace = true
+			t.where = wherePattern.MatchString(tail)
+			return -1
+		}
+		return i + 1 + brace
+	}
+
+	if t.headerLines >= maxHeaderLines {
+		t.header = nil
+	}
+	return -1
+}
+
+// 判断参数列表之后的内容是否符合函数定义，允许返回类型、修饰符和 throws 子句
+// 出现赋值、箭头函数或语句结束符时不是函数定义，exprBody 为真时允许紧邻左花括号的 =
+func isFunctionTail(tail string, brace int, exprBody bool) bool {
+	if brace >= 0 {
+		tail = tail[:brace]
+		if exprBody {
+			tail = strings.TrimSuffix(strings.TrimSpace(tail), "=")
+		}
+	}
+	return !strings.ContainsAny(strings.ReplaceAll(tail, "->", ""), "=;")
+}
+
+// 判断参数列表之后、左花括号之前的内容是否属于函数头
+// 允许空行、以 throws 等单词开头的子句、-> 或 : 开头的返回类型，以及 where 子句中的约束
+func (t *braceFunctionTracker) isHeaderContinuation(s string) bool {
+	trimmed := strings.TrimSpace(s)
+	if strings.Contains(trimmed, "}") || !isFunctionTail(trimmed, -1, false) {
+		return false
+	}
+	if wherePattern.MatchString(trimmed) {
+		t.where = true
+	}
+	return trimmed == "" || t.where || slices.Contains(headerContinuationWords, firstWord(trimmed)) ||
+		strings.HasPrefix(trimmed, "->") || strings.HasPrefix(trimmed, ":")
+}
+
+func (t *braceFunctionTracker) close(fn *FunctionStats, lineNo int) {
+	fn.Lines = lineNo - fn.Line + 1
+	t.functions = append(t.functions, fn)
+}
+
+func (t *braceFunctionTracker) finish(lineNo int) []*FunctionStats {
+	// 未闭合的函数延续到最后一行
+	for i := len(t.open) - 1; i >= 0; i-- {
+		t.close(t.open[i].fn, lineNo)
+	}
+	functions := t.functions
+	*t = braceFunctionTracker{exprBody: t.exprBody}
+	return functions
+}
+
+// indentFunctionTracker 通过 def 和缩进识别 Python 的函数
+type indentFunctionTracker struct {
+	header bool // 处于跨行的函数定义头中，如 black 格式化后每个参数一行的写法
+	depth  int  // 函数定义头中未闭合的括号数
+
+	open      []openFunction
+	functions []*FunctionStats
+}
+
+func (t *indentFunctionTracker) line(lineNo int, line string, res lineResult, inString bool) {
+	// 跨行字符串中的行和只有注释的行不影响函数边界
+	if !inString && res.hasCode {
+		code := strings.TrimSpace(res.code)
+		// 跨行的函数定义头（如单独一行的 ):）不结束任何函数
+		if !t.header {
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			for n := len(t.open); n > 0 && t.open[n-1].level >= indent; n = len(t.open) {
+				t.close(t.open[n-1])
+				t.open = t.open[:n-1]
+			}
+
+			if m := defPattern.FindStringSubmatch(code); m != nil {
+				t.open = append(t.open, openFunction{fn: &FunctionStats{Name: m[1], Line: lineNo}, level: indent})
+				t.header = true
+				t.depth = 0
+			}
+		}
+
+		// 函数定义头在括号闭合且没有续行符的行结束
+		if t.header {
+			t.depth += strings.Count(code, "(") + strings.Count(code, "[") -
+				strings.Count(code, ")") - strings.Count(code, "]")
+			if t.depth <= 0 && !strings.HasSuffix(code, `\`) {
+				t.header = false
+			}
+		}
+	}
+
+	if res.hasCode {
+		for i := range t.open {
+			t.open[i].last = lineNo
+		}
+	}
+}
+
+func (t *indentFunctionTracker) close(open openFunction) {
+	open.fn.Lines = open.last - open.fn.Line + 1
+	t.functions = append(t.functions, open.fn)
+}
+
+func (t *indentFunctionTracker) finish(int) []*FunctionStats {
+	for i := len(t.open) - 1; i >= 0; i-- {
+		t.close(t.open[i])
+	}
+	functions := t.functions
+	*t = indentFunctionTracker{}
+	return functions
+}
+
+// 获取行首的单词
+func firstWord(s string) string {
+	s = strings.TrimLeft(s, "}) \t")
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 按行统计源代码，返回识别出的函数名和行数
+func testFunctions(language, src string) map[string]int {
+	c := newLineCounter(language)
+	options := DefaultFileOptions()
+	for i, line := range strings.Split(src, "\n") {
+		c.count(line, i+1, options)
+	}
+	c.finishFunctions()
+
+	functions := make(map[string]int)
+	for _, fn := range c.functions {
+		functions[fn.Name] = fn.Lines
+	}
+	return functions
+}
+
+func TestFunctionTrackers(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		src      string
+		want     map[string]int
+	}{
+		{
+			name:     "python one-line signature",
+			language: "Python",
+			src:      "def foo(a, b):\n    x = a\n    return x\n\ndef bar():\n    pass\n",
+			want:     map[string]int{"foo": 3, "bar": 2},
+		},
+		{
+			name:     "python black-style multi-line signature",
+			language: "Python",
+			src: "def foo(\n    a,\n    b,\n):\n" +
+				strings.Repeat("    a += b\n", 8) +
+				"x = 1\n",
+			want: map[string]int{"foo": 12},
+		},
+		{
+			name:     "python backslash continuation in signature",
+			language: "Python",
+			src:      "def foo(a, \\\nb):\n    return a\n",
+			want:     map[string]int{"foo": 3},
+		},
+		{
+			name:     "java k&r braces",
+			language: "Java",
+			src:      "class A {\n    void foo(int x) {\n        x++;\n    }\n}\n",
+			want:     map[string]int{"foo": 3},
+		},
+		{
+			name:     "java allman braces",
+			language: "Java",
+			src:      "void foo(int x)\n{\n    x++;\n}\n",
+			want:     map[string]int{"foo": 4},
+		},
+		{
+			name:     "java throws clause on its own line",
+			language: "Java",
+			src:      "void bar(int x)\n    throws Exception\n{\n    x++;\n}\n",
+			want:     map[string]int{"bar": 5},
+		},
+		{
+			name:     "rust where clause",
+			language: "Rust",
+			src:      "fn f<T>(x: T) -> T\nwhere\n    T: Clone,\n    F: Fn(i32) -> i32,\n{\n    x\n}\n",
+			want:     map[string]int{"f": 7},
+		},
+		{
+			name:     "rust return type and brace on continuation line",
+			language: "Rust",
+			src:      "fn g(x: i32)\n    -> i32 {\n    x\n}\n",
+			want:     map[string]int{"g": 4},
+		},
+		{
+			name:     "scala expression body with braces",
+			language: "Scala",
+			src:      "def add(a: Int, b: Int): Int = {\n  a + b\n}\n",
+			want:     map[string]int{"add": 3},
+		},
+		{
+			name:     "kotlin expression body with braces",
+			language: "Kotlin",
+			src:      "fun run(x: Int) = {\n  println(x)\n}\n",
+			want:     map[string]int{"run": 3},
+		},
+		{
+			name:     "scala single-expression body is not a brace function",
+			language: "Scala",
+			src:      "def add(a: Int, b: Int): Int = a + b\n",
+			want:     map[string]int{},
+		},
+		{
+			name:     "assignment with braces is not a function in java",
+			language: "Java",
+			src:      "int[] xs = make(3) = {\n1 };\n",
+			want:     map[string]int{},
+		},
+		{
+			name:     "call statement without semicolon does not capture the next function",
+			language: "JavaScript",
+			src:      "init(config)\nfunction start(x) {\n  return x\n}\n",
+			want:     map[string]int{"start": 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testFunctions(tt.language, tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("functions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
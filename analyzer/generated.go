@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 生成代码的常见文件名模式（filepath.Match 语法，匹配文件名）
+var generatedFilePatterns = []string{
+	"*.pb.go", "*.pb.gw.go", "*.pb.validate.go", // protobuf / grpc-gateway
+	"*_pb2.py", "*_pb2_grpc.py", "*_pb2.pyi",
+	"*.pb.h", "*.pb.cc", "*_pb.js", "*_pb.d.ts", "*_grpc_pb.js",
+	"zz_generated.*.go", // Kubernetes code-generator
+	"*.generated.*", "*.g.dart", "*.freezed.dart", "*.Designer.cs", "*.designer.cs",
+	"bindata.go",
+}
+
+// 文件头部的生成代码标记
+var generatedHeaderPatterns = []*regexp.Regexp{
+	// Go 官方约定: https://golang.org/s/generatedcode
+	regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.\r?$`),
+	// protoc 生成的 Python、C++ 等代码
+	regexp.MustCompile(`Generated by the protocol buffer compiler\.\s+DO NOT EDIT!`),
+	regexp.MustCompile(`@generated\b`),
+	regexp.MustCompile(`<auto-generated`),
+	regexp.MustCompile(`(?i)\b(?:this|the following) (?:file|code) (?:is|was|has been) (?:automatically |auto-?)generated\b`),
+	regexp.MustCompile(`(?i)\bautogenerated file\b`),
+}
+
+// 检查生成代码标记的文件头行数
+const generatedHeaderLines = 20
+
+// 判断文件是否是生成的代码，extraPatterns 为用户配置的额外文件名模式
+func isGeneratedFile(path string, content []byte, extraPatterns []string) bool {
+	name := filepath.Base(path)
+	for _, patterns := range [][]string{generatedFilePatterns, extraPatterns} {
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+
+	head, _ := headAndTailLines(content, generatedHeaderLines)
+	header := strings.Join(head, "\n")
+	for _, re := range generatedHeaderPatterns {
+		if re.MatchString(header) {
+			return true
+		}
+	}
+	return false
+}
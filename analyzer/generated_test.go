@@ -0,0 +1,29 @@
+package analyzer
+
+import "testing"
+
+func TestIsGeneratedFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    bool
+	}{
+		{"go convention", "a.go", "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage a\n", true},
+		{"go convention with CRLF", "a.go", "// Code generated by stringer. DO NOT EDIT.\r\npackage a\r\n", true},
+		{"hand-written do not edit", "a.go", "// DO NOT EDIT the constant below without updating the docs.\npackage a\n", false},
+		{"code generated not at line start", "a.go", "package a\n\n// Code generated by hand. DO NOT EDIT. just kidding\n", false},
+		{"protoc python banner", "a.py", "# Generated by the protocol buffer compiler.  DO NOT EDIT!\n", true},
+		{"generated marker", "a.js", "/** @generated */\n", true},
+		{"filename pattern", "a.pb.go", "package a\n", true},
+		{"hand-written mock", "mock_store.go", "package a\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isGeneratedFile(tt.path, []byte(tt.content), nil); got != tt.want {
+				t.Errorf("isGeneratedFile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -529,3 +530,74 @@ func getBranchStats(path string) (map[string]bool, int, error) {
 
 	return branches, len(branches), nil
 }
+
+// 通过 git blame 获取注释标记所在行的作者和修改时间
+// 只处理包含标记的文件，未纳入版本控制的文件和未提交的行保持为空
+func blameMarkers(repoPath string, files []*FileStats) {
+	for _, fs := range files {
+		// Notebook 的行号按单元格源码计算，与文件中的行不对应
+		if len(fs.Markers) == 0 || fs.Notebook != nil {
+			continue
+		}
+		relPath, err := filepath.Rel(repoPath, fs.Path)
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command("git", "-C", repoPath, "blame", "--line-porcelain", "--", relPath)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			PrintInfo("无法获取文件的修改记录: %s (%v)", fs.Path, err)
+			continue
+		}
+
+		lines := parseBlame(out.String())
+		for _, marker := range fs.Markers {
+			if line, ok := lines[marker.Line]; ok {
+				marker.Author = line.author
+				marker.Date = line.date
+			}
+		}
+	}
+}
+
+// blameLine 是 git blame 中一行的最后修改信息
+type blameLine struct {
+	author string
+	date   time.Time
+}
+
+// 解析 git blame --line-porcelain 的输出，返回按行号索引的修改信息，跳过未提交的行
+func parseBlame(output string) map[int]blameLine {
+	res := make(map[int]blameLine)
+	var (
+		lineNo    int
+		current   blameLine
+		committed bool
+	)
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// 行内容，表示该行的信息结束
+			if committed {
+				res[lineNo] = current
+			}
+			lineNo, current = 0, blameLine{}
+		case strings.HasPrefix(line, "author "):
+			current.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if ts, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				current.date = time.Unix(ts, 0)
+			}
+		case lineNo == 0:
+			// 头部: <提交> <原行号> <行号> [<行数>]
+			parts := strings.Fields(line)
+			if len(parts) >= 3 {
+				lineNo, _ = strconv.Atoi(parts[2])
+				committed = strings.Trim(parts[0], "0") != ""
+			}
+		}
+	}
+	return res
+}
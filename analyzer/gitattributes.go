@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LinguistAttributes 存储文件在 .gitattributes 中声明的 linguist 属性
+type LinguistAttributes struct {
+	Language      string // linguist-language 指定的语言
+	Generated     bool   // linguist-generated
+	GeneratedSet  bool   // 是否显式设置了 linguist-generated（包括 linguist-generated=false）
+	Vendored      bool   // linguist-vendored
+	Documentation bool   // linguist-documentation
+}
+
+// gitAttributeRule 表示 .gitattributes 中的一行规则
+type gitAttributeRule struct {
+	dir     string            // 规则所在目录，相对于分析根目录，根目录为空字符串
+	pattern *regexp.Regexp    // 路径匹配规则，匹配相对于 dir 的路径
+	attrs   map[string]string // 属性值，"true"/"false" 或具体值，空字符串表示取消设置
+}
+
+// gitAttributes 存储分析目录中所有 .gitattributes 文件的规则
+type gitAttributes struct {
+	rules []gitAttributeRule // 按目录深度和行号排序，后面的规则覆盖前面的规则
+}
+
+// 加载 .gitattributes 文件，files 为文件的绝对或相对路径，root 为分析根目录
+func loadGitAttributes(root string, files []string) *gitAttributes {
+	// 上层目录的规则先生效，下层目录的规则覆盖上层
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.Count(filepath.ToSlash(files[i]), "/") < strings.Count(filepath.ToSlash(files[j]), "/")
+	})
+
+	attrs := &gitAttributes{}
+	for _, file := range files {
+		dir, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			continue
+		}
+		dir = filepath.ToSlash(dir)
+		if dir == "." {
+			dir = ""
+		}
+
+		rules, err := parseGitAttributes(dir, file)
+		if err != nil {
+			PrintWarning("无法读取 .gitattributes: %s (%v)", file, err)
+			continue
+		}
+		attrs.rules = append(attrs.rules, rules...)
+		PrintInfo("已加载 .gitattributes: %s (%d 条规则)", file, len(rules))
+	}
+	return attrs
+}
+
+// 解析单个 .gitattributes 文件
+func parseGitAttributes(dir, file string) ([]gitAttributeRule, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []gitAttributeRule
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		fields := strings.Fields(scanner.Text())
+		// 跳过空行、注释和宏定义
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+
+		pattern := compileGitPattern(fields[0])
+		if pattern == nil {
+			continue
+		}
+
+		rule := gitAttributeRule{dir: dir, pattern: pattern, attrs: make(map[string]string)}
+		for _, attr := range fields[1:] {
+			switch {
+			case strings.HasPrefix(attr, "-"):
+				rule.attrs[attr[1:]] = "false"
+			case strings.HasPrefix(attr, "!"):
+				rule.attrs[attr[1:]] = ""
+			default:
+				name, value, found := strings.Cut(attr, "=")
+				if !found {
+					value = "true"
+				}
+				rule.attrs[name] = value
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// 将 gitattributes 路径模式转换为正则表达式
+// 不含斜杠的模式匹配任意层级的文件名，含斜杠的模式相对于 .gitattributes 所在目录匹配
+func compileGitPattern(pattern string) *regexp.Regexp {
+	// gitattributes 中以斜杠结尾的模式不会匹配任何文件
+	if pattern == "" || strings.HasSuffix(pattern, "/") {
+		return nil
+	}
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			// 匹配零或多层目录
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// 获取文件的 linguist 属性，relPath 为相对于分析根目录的路径
+func (g *gitAttributes) linguist(relPath string) LinguistAttributes {
+	relPath = filepath.ToSlash(relPath)
+
+	values := make(map[string]string)
+	for _, rule := range g.rules {
+		target := relPath
+		if rule.dir != "" {
+			if !strings.HasPrefix(relPath, rule.dir+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(relPath, rule.dir+"/")
+		}
+		if !rule.pattern.MatchString(target) {
+			continue
+		}
+
+		for name, value := range rule.attrs {
+			if value == "" {
+				delete(values, name)
+			} else {
+				values[name] = value
+			}
+		}
+	}
+
+	res := LinguistAttributes{
+		Generated:     values["linguist-generated"] == "true",
+		GeneratedSet:  values["linguist-generated"] != "",
+		Vendored:      values["linguist-vendored"] == "true",
+		Documentation: values["linguist-documentation"] == "true",
+	}
+	if lang := values["linguist-language"]; lang != "" && lang != "true" && lang != "false" {
+		// linguist 使用连字符代替语言名称中的空格
+		res.Language = LookupLanguage(lang)
+		if res.Language == "" {
+			res.Language = strings.ReplaceAll(lang, "-", " ")
+		}
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompileGitPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"unanchored matches root", "*.go", "a.go", true},
+		{"unanchored matches nested", "*.go", "x/y/a.go", true},
+		{"star does not cross slash", "a*.go", "a/b.go", false},
+		{"anchored matches relative to dir", "/docs/*.md", "docs/a.md", true},
+		{"anchored does not match nested", "/docs/*.md", "x/docs/a.md", false},
+		{"pattern with slash is anchored", "docs/*.md", "x/docs/a.md", false},
+		{"double star slash matches zero dirs", "docs/**/gen.go", "docs/gen.go", true},
+		{"double star slash matches many dirs", "docs/**/gen.go", "docs/a/b/gen.go", true},
+		{"leading double star", "**/vendor/**", "a/vendor/b/c.go", true},
+		{"trailing double star", "vendor/**", "vendor/b/c.go", true},
+		{"trailing double star requires prefix", "vendor/**", "a/vendor/b.go", false},
+		{"question mark", "file?.txt", "file1.txt", true},
+		{"question mark does not match slash", "a?b", "a/b", false},
+		{"character class", "file[0-9].txt", "file1.txt", true},
+		{"character class mismatch", "file[0-9].txt", "filea.txt", false},
+		{"negated character class", "file[!0-9].txt", "filea.txt", true},
+		{"negated character class mismatch", "file[!0-9].txt", "file1.txt", false},
+		{"unclosed bracket is literal", "a[b", "a[b", true},
+		{"escaped star is literal", `a\*.go`, "a*.go", true},
+		{"escaped star does not glob", `a\*.go`, "ab.go", false},
+		{"dot is literal", "*.go", "a_go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := compileGitPattern(tt.pattern)
+			if re == nil {
+				t.Fatalf("compileGitPattern(%q) = nil", tt.pattern)
+			}
+			if got := re.MatchString(tt.path); got != tt.want {
+				t.Errorf("compileGitPattern(%q) match %q = %v, want %v (regexp %s)", tt.pattern, tt.path, got, tt.want, re)
+			}
+		})
+	}
+}
+
+func TestCompileGitPatternIgnored(t *testing.T) {
+	for _, pattern := range []string{"", "dir/", "/a/b/"} {
+		if re := compileGitPattern(pattern); re != nil {
+			t.Errorf("compileGitPattern(%q) = %s, want nil", pattern, re)
+		}
+	}
+}
+
+func TestGitAttributesLinguist(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitattributes": "*.pb.go linguist-generated\n" +
+			"gen/** linguist-generated=true\n" +
+			"gen/keep.go -linguist-generated\n" +
+			"gen/unset.go !linguist-generated\n" +
+			"third_party/** linguist-vendored\n",
+		"sub/.gitattributes": "*.tpl linguist-language=Go-Template\n" +
+			"/local.go linguist-documentation\n",
+	}
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	attrs := loadGitAttributes(root, paths)
+
+	tests := []struct {
+		path string
+		want LinguistAttributes
+	}{
+		{"a/b.pb.go", LinguistAttributes{Generated: true, GeneratedSet: true}},
+		{"gen/a.go", LinguistAttributes{Generated: true, GeneratedSet: true}},
+		{"gen/keep.go", LinguistAttributes{GeneratedSet: true}},
+		{"gen/unset.go", LinguistAttributes{}},
+		{"third_party/x/y.c", LinguistAttributes{Vendored: true}},
+		{"sub/a/page.tpl", LinguistAttributes{Language: "Go Template"}},
+		{"sub/local.go", LinguistAttributes{Documentation: true}},
+		{"sub/a/local.go", LinguistAttributes{}},
+		{"local.go", LinguistAttributes{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := attrs.linguist(tt.path); got != tt.want {
+				t.Errorf("linguist(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// GoStats 存储通过 go/ast 分析得到的 Go 代码结构统计
+type GoStats struct {
+	Functions  int // 函数数，不含方法
+	Methods    int // 方法数
+	Interfaces int // 接口类型数
+	Structs    int // 结构体类型数
+
+	Exported           int // 导出的顶层标识符数（函数、方法、类型、常量、变量），不含 _test.go 文件
+	Unexported         int // 未导出的顶层标识符数，不含 _test.go 文件
+	ExportedDocumented int // 有文档注释的导出标识符数
+
+	MaxCyclomatic   int // 最大圈复杂度
+	TotalCyclomatic int // 圈复杂度之和
+	MaxCognitive    int // 最大认知复杂度
+	TotalCognitive  int // 认知复杂度之和
+}
+
+// 合并统计信息
+func (s *GoStats) Merge(other *GoStats) {
+	s.Functions += other.Functions
+	s.Methods += other.Methods
+	s.Interfaces += other.Interfaces
+	s.Structs += other.Structs
+	s.Exported += other.Exported
+	s.Unexported += other.Unexported
+	s.ExportedDocumented += other.ExportedDocumented
+	s.MaxCyclomatic = max(s.MaxCyclomatic, other.MaxCyclomatic)
+	s.TotalCyclomatic += other.TotalCyclomatic
+	s.MaxCognitive = max(s.MaxCognitive, other.MaxCognitive)
+	s.TotalCognitive += other.TotalCognitive
+}
+
+// DocCoverage 返回导出标识符的文档注释覆盖率
+func (s *GoStats) DocCoverage() float64 {
+	if s.Exported == 0 {
+		return 0
+	}
+	return float64(s.ExportedDocumented) / float64(s.Exported)
+}
+
+// AvgCyclomatic 返回函数和方法的平均圈复杂度
+func (s *GoStats) AvgCyclomatic() float64 {
+	if s.Functions+s.Methods == 0 {
+		return 0
+	}
+	return float64(s.TotalCyclomatic) / float64(s.Functions+s.Methods)
+}
+
+// AvgCognitive 返回函数和方法的平均认知复杂度
+func (s *GoStats) AvgCognitive() float64 {
+	if s.Functions+s.Methods == 0 {
+		return 0
+	}
+	return float64(s.TotalCognitive) / float64(s.Functions+s.Methods)
+}
+
+// GoPackageStats 存储单个 Go 包的统计
+type GoPackageStats struct {
+	*GoStats
+
+	Name  string // 包名
+	Dir   string // 包所在目录，汇总统计中为相对于分析根目录的路径
+	Files int    // 文件数
+}
+
+// 使用 go/parser 分析 Go 源码，返回只包含该文件的包统计和每个函数的统计，解析失败时返回 nil
+func analyzeGoSource(path string, content []byte) (*GoPackageStats, []*FunctionStats) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		PrintWarning("无法解析 Go 文件: %s (%v)", path, err)
+		return nil, nil
+	}
+
+	// 测试文件中的标识符（如 TestX、BenchmarkX）不属于包的 API，不计入导出统计
+	api := !strings.HasSuffix(path, "_test.go")
+
+	res := &GoStats{}
+	var functions []*FunctionStats
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				res.Methods++
+			} else {
+				res.Functions++
+			}
+			if api {
+				res.countIdent(decl.Name, decl.Doc)
+			}
+
+			fn := &FunctionStats{
+				Name:       functionName(decl),
+				Line:       fset.Position(decl.Pos()).Line,
+				Lines:      fset.Position(decl.End()).Line - fset.Position(decl.Pos()).Line + 1,
+				Cyclomatic: cyclomaticComplexity(decl),
+				Cognitive:  cognitiveComplexity(decl),
+			}
+			functions = append(functions, fn)
+			res.MaxCyclomatic = max(res.MaxCyclomatic, fn.Cyclomatic)
+			res.TotalCyclomatic += fn.Cyclomatic
+			res.MaxCognitive = max(res.MaxCognitive, fn.Cognitive)
+			res.TotalCognitive += fn.Cognitive
+
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					switch spec.Type.(type) {
+					case *ast.InterfaceType:
+						res.Interfaces++
+					case *ast.StructType:
+						res.Structs++
+					}
+					if api {
+						res.countIdent(spec.Name, firstDoc(spec.Doc, decl.Doc))
+					}
+				case *ast.ValueSpec:
+					if !api {
+						continue
+					}
+					// 分组声明的文档注释对组内所有标识符有效
+					for _, name := range spec.Names {
+						res.countIdent(name, firstDoc(spec.Doc, decl.Doc))
+					}
+				}
+			}
+		}
+	}
+	return &GoPackageStats{GoStats: res, Name: file.Name.Name, Dir: filepath.Dir(path), Files: 1}, functions
+}
+
+// 统计顶层标识符的导出情况和文档注释
+func (s *GoStats) countIdent(name *ast.Ident, doc *ast.CommentGroup) {
+	if name.Name == "_" {
+		return
+	}
+	if !name.IsExported() {
+		s.Unexported++
+		return
+	}
+	s.Exported++
+	if doc != nil {
+		s.ExportedDocumented++
+	}
+}
+
+// 返回第一个非空的文档注释
+func firstDoc(docs ...*ast.CommentGroup) *ast.CommentGroup {
+	for _, doc := range docs {
+		if doc != nil {
+			return doc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package analyzer
+
+import "testing"
+
+func TestAnalyzeGoSourceExportedIdents(t *testing.T) {
+	tests := []struct {
+		name               string
+		path               string
+		src                string
+		exported           int
+		unexported         int
+		exportedDocumented int
+	}{
+		{
+			name: "package file",
+			path: "main.go",
+			src: `package main
+
+// Run 运行程序
+func Run() {}
+
+func Stop() {}
+
+func helper() {}
+
+// 分组声明的文档注释对组内所有标识符有效
+const (
+	A = 1
+	B = 2
+)
+`,
+			exported:           4,
+			unexported:         1,
+			exportedDocumented: 3,
+		},
+		{
+			name: "test file",
+			path: "main_test.go",
+			src: `package main
+
+import "testing"
+
+func TestRun(t *testing.T) {}
+
+func BenchmarkRun(b *testing.B) {}
+
+func ExampleRun() {}
+
+var fixture = 1
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, functions := analyzeGoSource(tt.path, []byte(tt.src))
+			if pkg == nil {
+				t.Fatal("analyzeGoSource() returned nil")
+			}
+			if pkg.Exported != tt.exported || pkg.Unexported != tt.unexported || pkg.ExportedDocumented != tt.exportedDocumented {
+				t.Errorf("Exported=%d Unexported=%d ExportedDocumented=%d, want %d %d %d",
+					pkg.Exported, pkg.Unexported, pkg.ExportedDocumented, tt.exported, tt.unexported, tt.exportedDocumented)
+			}
+			if pkg.Functions != len(functions) {
+				t.Errorf("Functions=%d, want %d", pkg.Functions, len(functions))
+			}
+		})
+	}
+}
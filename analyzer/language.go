@@ -1,12 +1,29 @@
 package analyzer
 
 import (
+	"fmt"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 )
 
-// LanguageStats 存储每种语言的统计信息
-type LanguageStats = Stat
+func init() {
+	// 启动时自检，避免新增语言时遗漏注释样式导致注释被静默计为代码
+	if err := CheckCommentPatterns(); err != nil {
+		panic(err)
+	}
+}
+
+// LanguageStats 存储每种语言的统计信息，测试代码和非测试代码另外分开统计
+type LanguageStats struct {
+	Stat
+	TestSplit
+}
+
+func newLanguageStats() *LanguageStats {
+	return &LanguageStats{TestSplit: newTestSplit()}
+}
 
 // GetLanguageByExt 根据文件扩展名确定编程语言
 func GetLanguageByExt(filename string) string {
@@ -19,130 +36,517 @@ func GetLanguageByExt(filename string) string {
 
 // 语言定义映射
 var languageExt = map[string]string{
-	".go":    "Go",
-	".java":  "Java",
-	".js":    "JavaScript",
-	".ts":    "TypeScript",
-	".jsx":   "React JSX",
-	".tsx":   "React TSX",
-	".py":    "Python",
-	".rb":    "Ruby",
-	".php":   "PHP",
-	".c":     "C",
-	".cpp":   "C++",
-	".h":     "C/C++ Header",
-	".hpp":   "C++ Header",
-	".cs":    "C#",
-	".swift": "Swift",
-	".kt":    "Kotlin",
-	".rs":    "Rust",
-	".html":  "HTML",
-	".css":   "CSS",
-	".scss":  "SCSS",
-	".less":  "LESS",
-	".json":  "JSON",
-	".xml":   "XML",
-	".yaml":  "YAML",
-	".yml":   "YAML",
-	".md":    "Markdown",
-	".txt":   "Text",
-	".sh":    "Shell",
-	".bat":   "Batch",
-	".ps1":   "PowerShell",
-	".sql":   "SQL",
-	".r":     "R",
-	".dart":  "Dart",
-	".lua":   "Lua",
-	".ex":    "Elixir",
-	".exs":   "Elixir",
-	".erl":   "Erlang",
-	".hrl":   "Erlang",
-	".clj":   "Clojure",
-	".elm":   "Elm",
-	".hs":    "Haskell",
-	".pl":    "Perl",
-	".pm":    "Perl",
+	".go":       "Go",
+	".java":     "Java",
+	".js":       "JavaScript",
+	".ts":       "TypeScript",
+	".jsx":      "React JSX",
+	".tsx":      "React TSX",
+	".py":       "Python",
+	".rb":       "Ruby",
+	".php":      "PHP",
+	".c":        "C",
+	".cpp":      "C++",
+	".h":        "C/C++ Header",
+	".hpp":      "C++ Header",
+	".cs":       "C#",
+	".swift":    "Swift",
+	".kt":       "Kotlin",
+	".rs":       "Rust",
+	".scala":    "Scala",
+	".sc":       "Scala",
+	".ml":       "OCaml",
+	".mli":      "OCaml",
+	".html":     "HTML",
+	".htm":      "HTML",
+	".vue":      "Vue",
+	".svelte":   "Svelte",
+	".css":      "CSS",
+	".scss":     "SCSS",
+	".less":     "LESS",
+	".json":     "JSON",
+	".xml":      "XML",
+	".yaml":     "YAML",
+	".yml":      "YAML",
+	".md":       "Markdown",
+	".markdown": "Markdown",
+	".ipynb":    NotebookLanguage,
+	".txt":      "Text",
+	".sh":       "Shell",
+	".bat":      "Batch",
+	".ps1":      "PowerShell",
+	".sql":      "SQL",
+	".r":        "R",
+	".jl":       "Julia",
+	".dart":     "Dart",
+	".lua":      "Lua",
+	".ex":       "Elixir",
+	".exs":      "Elixir",
+	".erl":      "Erlang",
+	".hrl":      "Erlang",
+	".clj":      "Clojure",
+	".elm":      "Elm",
+	".hs":       "Haskell",
+	".pl":       "Perl",
+	".pm":       "Perl",
+	".m":        "Objective-C", // 与 MATLAB 共用，根据内容判断
+	".mm":       "Objective-C",
+	".groovy":   "Groovy",
+	".gradle":   "Groovy",
+	".bzl":      "Starlark",
+	".star":     "Starlark",
+	".cmake":    "CMake",
+	".mk":       "Makefile",
+}
+
+// CommentStyle 描述一种语言的注释与字符串字面量语法
+type CommentStyle struct {
+	SingleLine []string      // 单行注释标记
+	MultiStart []string      // 多行注释开始标记
+	MultiEnd   []string      // 多行注释结束标记（与 MultiStart 一一对应）
+	Nested     bool          // 多行注释是否可以嵌套（如 Rust 的 /* /* */ */）
+	LineStart  []string      // 仅在行首有效的注释标记（如 Perl 的 =pod、批处理的 REM）
+	Strings    []StringStyle // 字符串字面量，其中出现的注释标记会被忽略
+	Heredoc    []string      // here document 开始标记（如 Shell 的 <<），之后到结束标记所在行的内容视为字符串
 }
 
+// 检查注释样式是否有效，空的注释标记或定界符会使词法分析无法前进
+func (s CommentStyle) validate() error {
+	if len(s.MultiStart) != len(s.MultiEnd) {
+		return fmt.Errorf("多行注释开始与结束标记数量不一致")
+	}
+	for _, markers := range [][]string{s.SingleLine, s.MultiStart, s.MultiEnd, s.Heredoc} {
+		if slices.Contains(markers, "") {
+			return fmt.Errorf("注释标记不能为空")
+		}
+	}
+	for _, str := range s.Strings {
+		if str.Start == "" || str.End == "" {
+			return fmt.Errorf("字符串定界符不能为空")
+		}
+	}
+	return nil
+}
+
+// StringStyle 描述一种字符串字面量的定界符
+type StringStyle struct {
+	Start     string `json:"start"`     // 开始定界符
+	End       string `json:"end"`       // 结束定界符
+	Escape    string `json:"escape"`    // 转义字符，为空表示不支持转义（如 Go 的原始字符串）
+	MultiLine bool   `json:"multiLine"` // 是否允许跨行
+	Char      bool   `json:"char"`      // 字符字面量，定界符之间只能是一个字符或一个转义序列，否则不视为字面量
+}
+
+// 常见的字符串字面量定义
+var (
+	// 双引号字符串
+	doubleQuoted = StringStyle{Start: `"`, End: `"`, Escape: `\`}
+	// 单引号字符串或字符
+	singleQuoted = StringStyle{Start: "'", End: "'", Escape: `\`}
+	// 字符字面量，单引号同时用于其他语法（如 Rust 的生命周期、Haskell 的 x'）的语言使用
+	charLiteral = StringStyle{Start: "'", End: "'", Escape: `\`, Char: true}
+	// 模板字符串（JavaScript 系列）
+	templateString = StringStyle{Start: "`", End: "`", Escape: `\`, MultiLine: true}
+	// C 系语言的字符串与字符
+	cStrings = []StringStyle{doubleQuoted, singleQuoted}
+	// JavaScript 系列语言的字符串
+	jsStrings = []StringStyle{doubleQuoted, singleQuoted, templateString}
+)
+
 // CommentPatterns 存储不同语言的注释样式
-var CommentPatterns = map[string]struct {
-	SingleLine []string
-	MultiStart []string
-	MultiEnd   []string
-}{
+var CommentPatterns = map[string]CommentStyle{
 	"Go": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: "`", End: "`", MultiLine: true}, // 原始字符串
+		},
 	},
 	"Java": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true}, // 文本块
+		},
 	},
 	"JavaScript": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings:    jsStrings,
 	},
 	"TypeScript": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings:    jsStrings,
 	},
 	"Python": {
 		SingleLine: []string{"#"},
-		MultiStart: []string{"'''", "\"\"\""},
-		MultiEnd:   []string{"'''", "\"\"\""},
+		// 三引号字符串是普通的字符串字面量，文档字符串由 docstringTracker 识别
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+			{Start: "'''", End: "'''", Escape: `\`, MultiLine: true},
+		},
 	},
 	"Ruby": {
 		SingleLine: []string{"#"},
 		MultiStart: []string{"=begin"},
 		MultiEnd:   []string{"=end"},
+		LineStart:  []string{"=begin"},
+		Strings:    cStrings,
+	},
+	"Perl": {
+		SingleLine: []string{"#"},
+		// POD 文档块以行首的 =指令 开始，以 =cut 结束
+		MultiStart: []string{"=pod", "=head1", "=head2", "=head3", "=head4", "=over", "=begin", "=for", "=encoding"},
+		MultiEnd:   []string{"=cut", "=cut", "=cut", "=cut", "=cut", "=cut", "=cut", "=cut", "=cut"},
+		LineStart:  []string{"=pod", "=head1", "=head2", "=head3", "=head4", "=over", "=begin", "=for", "=encoding"},
+		Strings:    cStrings,
+	},
+	"Lua": {
+		SingleLine: []string{"--"},
+		MultiStart: []string{"--[["},
+		MultiEnd:   []string{"]]"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: "[[", End: "]]", MultiLine: true}, // 长字符串
+		},
+	},
+	"R": {
+		SingleLine: []string{"#"},
+		Strings:    cStrings,
+	},
+	"Julia": {
+		SingleLine: []string{"#"},
+		MultiStart: []string{"#="},
+		MultiEnd:   []string{"=#"},
+		Nested:     true,
+		// 单引号同时用于转置运算符（如 A'）
+		Strings: []StringStyle{
+			doubleQuoted, charLiteral,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+		},
+	},
+	"Elixir": {
+		SingleLine: []string{"#"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true}, // heredoc
+			{Start: "'''", End: "'''", Escape: `\`, MultiLine: true},
+		},
+	},
+	"Erlang": {
+		SingleLine: []string{"%"},
+		// $" 等字符字面量中的引号没有配对，字符串不允许跨行以免影响之后的所有行
+		Strings: []StringStyle{doubleQuoted},
+	},
+	"Clojure": {
+		SingleLine: []string{";"},
+		Strings:    []StringStyle{{Start: `"`, End: `"`, Escape: `\`, MultiLine: true}},
+	},
+	"Elm": {
+		SingleLine: []string{"--"},
+		MultiStart: []string{"{-"},
+		MultiEnd:   []string{"-}"},
+		Nested:     true,
+		Strings: []StringStyle{
+			doubleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+		},
+	},
+	"React JSX": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    jsStrings,
+	},
+	"React TSX": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    jsStrings,
+	},
+	"Dart": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Nested:     true,
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+			{Start: "'''", End: "'''", Escape: `\`, MultiLine: true},
+		},
 	},
 	"PHP": {
 		SingleLine: []string{"//", "#"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings: []StringStyle{
+			{Start: `"`, End: `"`, Escape: `\`, MultiLine: true},
+			{Start: "'", End: "'", Escape: `\`, MultiLine: true},
+		},
+		Heredoc: []string{"<<<"}, // heredoc 与 nowdoc，内容中的引号不需要配对
 	},
 	"C": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
 	},
 	"C++": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"C/C++ Header": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"C++ Header": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"Objective-C": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"MATLAB": {
+		SingleLine: []string{"%"},
+		MultiStart: []string{"%{"},
+		MultiEnd:   []string{"%}"},
+		LineStart:  []string{"%{"},
+		// 单引号同时用于转置运算符，不作为字符串处理
+		Strings: []StringStyle{{Start: `"`, End: `"`}},
+	},
+	"Groovy": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+			{Start: "'''", End: "'''", Escape: `\`, MultiLine: true},
+		},
 	},
 	"C#": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `@"`, End: `"`, MultiLine: true}, // 逐字字符串
+		},
 	},
 	"Rust": {
 		SingleLine: []string{"//"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Nested:     true,
+		// 单引号同时用于生命周期标注，只识别完整的字符字面量
+		Strings: []StringStyle{{Start: `"`, End: `"`, Escape: `\`, MultiLine: true}, charLiteral},
+	},
+	"Swift": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Nested:     true,
+		Strings: []StringStyle{
+			doubleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true}, // 多行字符串
+		},
+	},
+	"Kotlin": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Nested:     true,
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, MultiLine: true}, // 原始字符串
+		},
+	},
+	"Scala": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Nested:     true,
+		// 单引号同时用于符号字面量，只识别完整的字符字面量
+		Strings: []StringStyle{
+			doubleQuoted, charLiteral,
+			{Start: `"""`, End: `"""`, MultiLine: true}, // 原始字符串
+		},
+	},
+	"Haskell": {
+		SingleLine: []string{"--"},
+		MultiStart: []string{"{-"},
+		MultiEnd:   []string{"-}"},
+		Nested:     true,
+		// 单引号同时用于标识符（如 x'），只识别完整的字符字面量
+		Strings: []StringStyle{doubleQuoted, charLiteral},
+	},
+	"OCaml": {
+		SingleLine: []string{},
+		MultiStart: []string{"(*"},
+		MultiEnd:   []string{"*)"},
+		Nested:     true,
+		Strings:    []StringStyle{{Start: `"`, End: `"`, Escape: `\`, MultiLine: true}},
 	},
 	"HTML": {
 		SingleLine: []string{},
 		MultiStart: []string{"<!--"},
 		MultiEnd:   []string{"-->"},
 	},
+	"Vue": {
+		SingleLine: []string{},
+		MultiStart: []string{"<!--"},
+		MultiEnd:   []string{"-->"},
+	},
+	"Svelte": {
+		SingleLine: []string{},
+		MultiStart: []string{"<!--"},
+		MultiEnd:   []string{"-->"},
+	},
+	"XML": {
+		MultiStart: []string{"<!--"},
+		MultiEnd:   []string{"-->"},
+	},
+	"Markdown": {
+		MultiStart: []string{"<!--"},
+		MultiEnd:   []string{"-->"},
+	},
+	// Notebook 按单元格统计，代码单元格使用内核语言的注释样式
+	NotebookLanguage: {},
 	"CSS": {
 		SingleLine: []string{},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"SCSS": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	"LESS": {
+		SingleLine: []string{"//"},
+		MultiStart: []string{"/*"},
+		MultiEnd:   []string{"*/"},
+		Strings:    cStrings,
+	},
+	// JSON 和纯文本没有注释语法
+	"JSON": {
+		Strings: []StringStyle{doubleQuoted},
+	},
+	"Text": {},
+	"YAML": {
+		SingleLine: []string{"#"},
+		// YAML 单引号字符串通过连续两个单引号转义
+		Strings: []StringStyle{doubleQuoted, {Start: "'", End: "'"}},
 	},
 	"Shell": {
 		SingleLine: []string{"#"},
 		MultiStart: []string{},
 		MultiEnd:   []string{},
+		Strings: []StringStyle{
+			{Start: `"`, End: `"`, Escape: `\`, MultiLine: true},
+			{Start: "'", End: "'", MultiLine: true},
+		},
+		Heredoc: []string{"<<"}, // here document，内容中的引号不需要配对
+	},
+	"Makefile": {
+		SingleLine: []string{"#"},
+	},
+	"Dockerfile": {
+		SingleLine: []string{"#"},
+		LineStart:  []string{"#"},
+		Strings:    cStrings,
+	},
+	"CMake": {
+		SingleLine: []string{"#"},
+		MultiStart: []string{"#[["},
+		MultiEnd:   []string{"]]"},
+		Strings:    []StringStyle{{Start: `"`, End: `"`, Escape: `\`, MultiLine: true}},
+	},
+	"Starlark": {
+		SingleLine: []string{"#"},
+		Strings: []StringStyle{
+			doubleQuoted, singleQuoted,
+			{Start: `"""`, End: `"""`, Escape: `\`, MultiLine: true},
+			{Start: "'''", End: "'''", Escape: `\`, MultiLine: true},
+		},
+	},
+	"Batch": {
+		SingleLine: []string{"REM ", "rem ", "Rem ", "@REM ", "@rem ", "::"},
+		LineStart:  []string{"REM ", "rem ", "Rem ", "@REM ", "@rem ", "::"},
+		Strings:    []StringStyle{{Start: `"`, End: `"`}},
+	},
+	"PowerShell": {
+		SingleLine: []string{"#"},
+		MultiStart: []string{"<#"},
+		MultiEnd:   []string{"#>"},
+		// PowerShell 使用反引号转义
+		Strings: []StringStyle{
+			{Start: `"`, End: `"`, Escape: "`", MultiLine: true},
+			{Start: "'", End: "'", MultiLine: true},
+		},
 	},
 	"SQL": {
 		SingleLine: []string{"--"},
 		MultiStart: []string{"/*"},
 		MultiEnd:   []string{"*/"},
+		// SQL 通过连续两个引号转义，无需转义字符
+		Strings: []StringStyle{{Start: "'", End: "'"}, {Start: `"`, End: `"`}},
 	},
 }
+
+// CheckCommentPatterns 检查每种可识别的语言都定义了完整的注释样式
+func CheckCommentPatterns() error {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, lang := range knownLanguages() {
+		if seen[lang] {
+			continue
+		}
+		seen[lang] = true
+
+		style, ok := CommentPatterns[lang]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: 缺少注释样式", lang))
+			continue
+		}
+		if err := style.validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", lang, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("语言定义不完整: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// 返回所有可以被识别出的语言
+func knownLanguages() []string {
+	var langs []string
+	for _, table := range []map[string]string{languageExt, languageFilenames, shebangInterpreters, languageAliases} {
+		for _, lang := range table {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
@@ -0,0 +1,335 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 词法记号类型
+type tokenKind int
+
+const (
+	tokenSingleLine tokenKind = iota // 单行注释开始
+	tokenMultiStart                  // 多行注释开始
+	tokenString                      // 字符串字面量开始
+	tokenHeredoc                     // here document 开始，如 Shell 的 <<EOF
+)
+
+// 词法分析所处的状态
+type lexMode int
+
+const (
+	modeCode    lexMode = iota // 代码
+	modeComment                // 多行注释
+	modeString                 // 字符串字面量
+	modeHeredoc                // here document 的内容
+)
+
+// lexToken 表示一个可以改变词法状态的记号
+type lexToken struct {
+	text string       // 记号文本
+	kind tokenKind    // 记号类型
+	bol  bool         // 是否仅在行首有效
+	end  string       // 多行注释的结束标记
+	str  *StringStyle // 字符串字面量定义
+}
+
+// lineResult 存储单行的词法分析结果
+type lineResult struct {
+	hasCode    bool   // 是否包含代码（字符串字面量也视为代码）
+	hasComment bool   // 是否包含注释
+	code       string // 去除注释和字符串字面量后的代码，注释和字面量替换为空格
+	stripped   string // 去除注释和空白后的代码，保留字符串字面量，用于比较代码是否相同
+	comment    string // 注释的内容，不含注释标记，同一行的多段注释以空格分隔
+}
+
+// lexer 是按行驱动的注释与字符串状态机
+// 多行注释和多行字符串的状态会跨行保留，字面量中出现的注释标记不会被识别为注释
+type lexer struct {
+	tokens []lexToken // 按长度降序排列的记号，保证最长匹配
+	nested bool       // 多行注释是否可以嵌套
+
+	mode         lexMode      // 当前状态
+	commentStart string       // 当前多行注释的开始标记
+	commentEnd   string       // 当前多行注释的结束标记
+	depth        int          // 当前多行注释的嵌套深度
+	str          *StringStyle // 当前字符串字面量定义
+	heredocs     []string     // 等待结束的 here document 结束标记，按出现顺序排列
+
+	buf      []byte // 当前行的代码，在各行之间复用
+	stripped []byte // 当前行去除注释和空白后的代码，在各行之间复用
+	comment  []byte // 当前行的注释内容，在各行之间复用
+}
+
+// 根据注释样式创建词法分析器
+func newLexer(style CommentStyle) *lexer {
+	l := &lexer{nested: style.Nested}
+	for _, marker := range style.SingleLine {
+		l.tokens = append(l.tokens, lexToken{text: marker, kind: tokenSingleLine})
+	}
+	for i, marker := range style.MultiStart {
+		// 确保有匹配的结束标记
+		if i >= len(style.MultiEnd) {
+			break
+		}
+		l.tokens = append(l.tokens, lexToken{text: marker, kind: tokenMultiStart, end: style.MultiEnd[i]})
+	}
+	for i := range l.tokens {
+		l.tokens[i].bol = slices.Contains(style.LineStart, l.tokens[i].text)
+	}
+	for _, marker := range style.Heredoc {
+		l.tokens = append(l.tokens, lexToken{text: marker, kind: tokenHeredoc})
+	}
+	for i := range style.Strings {
+		l.tokens = append(l.tokens, lexToken{text: style.Strings[i].Start, kind: tokenString, str: &style.Strings[i]})
+	}
+
+	// 长记号优先，例如 Python 的 ''' 需要先于 ' 匹配
+	sort.SliceStable(l.tokens, func(i, j int) bool {
+		return len(l.tokens[i].text) > len(l.tokens[j].text)
+	})
+	return l
+}
+
+// 分析一行文本，并更新跨行状态
+func (l *lexer) scanLine(line string) lineResult {
+	var res lineResult
+	l.buf = l.buf[:0]
+	l.stripped = l.stripped[:0]
+	l.comment = l.comment[:0]
+	if l.mode == modeHeredoc {
+		// here document 的内容整行视为字符串字面量，直到遇到结束标记所在的行
+		res.hasCode = true
+		l.stripped = append(l.stripped, line...)
+		if isHeredocEnd(line, l.heredocs[0]) {
+			if l.heredocs = l.heredocs[1:]; len(l.heredocs) == 0 {
+				l.mode = modeCode
+			}
+		}
+		res.stripped = string(l.stripped)
+		return res
+	}
+	for i := 0; i < len(line); {
+		switch l.mode {
+		case modeComment:
+			res.hasComment = true
+			start := i
+			i = l.skipComment(line, i)
+			end := i
+			if l.mode != modeComment {
+				end -= len(l.commentEnd)
+			}
+			l.appendComment(line[start:end])
+
+		case modeString:
+			res.hasCode = true
+			start := i
+			i = l.skipString(line, i)
+			l.stripped = append(l.stripped, line[start:i]...)
+
+		default:
+			if isSpace(line[i]) {
+				l.buf = append(l.buf, ' ')
+				i++
+				continue
+			}
+
+			tok := l.match(line[i:], res.hasCode)
+			if tok != nil && tok.kind == tokenHeredoc {
+				// here document 从下一行开始，本行的其余部分继续按代码分析
+				if word, n := heredocWord(line[i+len(tok.text):]); word != "" {
+					l.heredocs = append(l.heredocs, word)
+					res.hasCode = true
+					l.buf = append(l.buf, line[i:i+len(tok.text)+n]...)
+					l.stripped = append(l.stripped, line[i:i+len(tok.text)+n]...)
+					i += len(tok.text) + n
+					continue
+				}
+				tok = nil
+			}
+			if tok == nil {
+				res.hasCode = true
+				l.buf = append(l.buf, line[i])
+				l.stripped = append(l.stripped, line[i])
+				i++
+				continue
+			}
+
+			l.buf = append(l.buf, ' ')
+			if tok.kind == tokenString {
+				l.stripped = append(l.stripped, tok.text...)
+			}
+			i += len(tok.text)
+			switch tok.kind {
+			case tokenSingleLine:
+				res.hasComment = true
+				l.appendComment(line[i:])
+				i = len(line)
+			case tokenMultiStart:
+				res.hasComment = true
+				l.mode = modeComment
+				l.commentStart = tok.text
+				l.commentEnd = tok.end
+				l.depth = 1
+			case tokenString:
+				res.hasCode = true
+				l.mode = modeString
+				l.str = tok.str
+			}
+		}
+	}
+
+	// 不允许跨行的字符串在行尾结束
+	if l.mode == modeString && !l.str.MultiLine {
+		l.mode = modeCode
+	}
+	if l.mode == modeCode && len(l.heredocs) > 0 {
+		l.mode = modeHeredoc
+	}
+	res.code = string(l.buf)
+	res.stripped = string(l.stripped)
+	res.comment = string(l.comment)
+	return res
+}
+
+// 记录一段注释内容
+func (l *lexer) appendComment(text string) {
+	if len(l.comment) > 0 {
+		l.comment = append(l.comment, ' ')
+	}
+	l.comment = append(l.comment, text...)
+}
+
+// 跳过多行注释的内容，返回注释结束后的位置
+// 可嵌套的注释需要遇到与开始标记数量相同的结束标记才会结束
+func (l *lexer) skipComment(line string, i int) int {
+	for i < len(line) {
+		end := strings.Index(line[i:], l.commentEnd)
+		if l.nested {
+			// 嵌套的开始标记出现在结束标记之前，深度加一
+			if start := strings.Index(line[i:], l.commentStart); start >= 0 && (end < 0 || start < end) {
+				l.depth++
+				i += start + len(l.commentStart)
+				continue
+			}
+		}
+		if end < 0 {
+			return len(line)
+		}
+
+		i += end + len(l.commentEnd)
+		if l.depth--; !l.nested || l.depth == 0 {
+			l.mode = modeCode
+			return i
+		}
+	}
+	return i
+}
+
+// 跳过字符串字面量的内容，返回字符串结束后的位置
+func (l *lexer) skipString(line string, i int) int {
+	for i < len(line) {
+		if l.str.Escape != "" && strings.HasPrefix(line[i:], l.str.Escape) {
+			i += len(l.str.Escape) + 1
+			continue
+		}
+		if strings.HasPrefix(line[i:], l.str.End) {
+			l.mode = modeCode
+			return i + len(l.str.End)
+		}
+		i++
+	}
+	return len(line)
+}
+
+// 匹配当前位置的记号，afterCode 表示当前位置之前已经出现过代码
+func (l *lexer) match(s string, afterCode bool) *lexToken {
+	for i := range l.tokens {
+		if l.tokens[i].bol && afterCode {
+			continue
+		}
+		if !strings.HasPrefix(s, l.tokens[i].text) {
+			continue
+		}
+		if str := l.tokens[i].str; str != nil && str.Char && !isCharLiteral(s[len(str.Start):], str) {
+			continue
+		}
+		return &l.tokens[i]
+	}
+	return nil
+}
+
+// 判断开始定界符之后的内容是否构成完整的字符字面量，如 'a'、'"'、'\n'、'\u{1F600}' 或转义的单引号
+func isCharLiteral(s string, str *StringStyle) bool {
+	switch {
+	case str.Escape != "" && strings.HasPrefix(s, str.Escape):
+		// 转义序列：转义字符之后的一个字符，以及 \x7f、\u{1F600} 等序列中的十六进制数字和花括号
+		s = s[len(str.Escape):]
+		if s == "" {
+			return false
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		s = strings.TrimLeft(s[size:], "0123456789abcdefABCDEF{}")
+	case s == "" || strings.HasPrefix(s, str.End):
+		return false
+	default:
+		_, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+	}
+	return strings.HasPrefix(s, str.End)
+}
+
+// 解析 here document 开始标记之后的结束标记，如 EOF、-EOF、'EOF'、"EOF"
+// 返回结束标记和消耗的字节数，不构成 here document（如 1<<2、<<<）时返回空
+func heredocWord(s string) (string, int) {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	quote := byte(0)
+	if i < len(s) && (s[i] == '\'' || s[i] == '"') {
+		quote = s[i]
+		i++
+	}
+	start := i
+	for i < len(s) && (isWordByte(s[i]) || i > start && s[i] >= '0' && s[i] <= '9') {
+		i++
+	}
+	word := s[start:i]
+	if word == "" {
+		return "", 0
+	}
+	if quote != 0 {
+		if i >= len(s) || s[i] != quote {
+			return "", 0
+		}
+		i++
+	}
+	return word, i
+}
+
+// 判断一行是否是 here document 的结束行，结束标记前可以有缩进（<<- 和 PHP 7.3 的写法）
+// 结束标记之后只允许出现 ;、,、) 等标点，如 PHP 的 EOT;
+func isHeredocEnd(line, word string) bool {
+	rest, found := strings.CutPrefix(strings.TrimLeft(line, " \t"), word)
+	if !found {
+		return false
+	}
+	rest = strings.TrimRight(rest, " \t\r")
+	return rest == "" || !isWordByte(rest[0]) && !isSpace(rest[0]) && (rest[0] < '0' || rest[0] > '9')
+}
+
+// 判断是否是标识符的首字符
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// 判断是否是空白字符
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v'
+}
@@ -0,0 +1,196 @@
+package analyzer
+
+import "testing"
+
+func TestLexerScanLine(t *testing.T) {
+	type want struct {
+		hasCode    bool
+		hasComment bool
+		comment    string
+	}
+	tests := []struct {
+		name     string
+		language string
+		lines    []string
+		want     []want
+	}{
+		{
+			name:     "code only",
+			language: "Go",
+			lines:    []string{`x := 1`},
+			want:     []want{{hasCode: true}},
+		},
+		{
+			name:     "mixed line",
+			language: "Go",
+			lines:    []string{`x := 1 // reset`},
+			want:     []want{{hasCode: true, hasComment: true, comment: " reset"}},
+		},
+		{
+			name:     "comment marker inside string",
+			language: "Go",
+			lines:    []string{`url := "http://example.com" /* host */`},
+			want:     []want{{hasCode: true, hasComment: true, comment: " host "}},
+		},
+		{
+			name:     "escaped quote inside string",
+			language: "Go",
+			lines:    []string{`s := "a \" // not a comment"`, `// comment`},
+			want:     []want{{hasCode: true}, {hasComment: true, comment: " comment"}},
+		},
+		{
+			name:     "raw string spans lines",
+			language: "Go",
+			lines:    []string{"s := `first", "// still string", "end`"},
+			want:     []want{{hasCode: true}, {hasCode: true}, {hasCode: true}},
+		},
+		{
+			name:     "block comment spans lines",
+			language: "Go",
+			lines:    []string{"/* start", "middle", "end */ x := 1"},
+			want: []want{
+				{hasComment: true, comment: " start"},
+				{hasComment: true, comment: "middle"},
+				{hasCode: true, hasComment: true, comment: "end "},
+			},
+		},
+		{
+			name:     "nested comments",
+			language: "Rust",
+			lines:    []string{"/* outer /* inner */ still comment", "*/ let x = 1;"},
+			want: []want{
+				{hasComment: true, comment: " outer /* inner */ still comment"},
+				{hasCode: true, hasComment: true},
+			},
+		},
+		{
+			name:     "non-nested comments end at first marker",
+			language: "Go",
+			lines:    []string{"/* outer /* inner */ x := 1"},
+			want:     []want{{hasCode: true, hasComment: true, comment: " outer /* inner "}},
+		},
+		{
+			name:     "rust char literal with double quote",
+			language: "Rust",
+			lines:    []string{`let c = '"'; // note`, `let d = 1; // next`},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " note"},
+				{hasCode: true, hasComment: true, comment: " next"},
+			},
+		},
+		{
+			name:     "rust escaped quote char literal",
+			language: "Rust",
+			lines:    []string{`let c = '\''; // note`, `let e = '\u{1F600}'; // emoji`},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " note"},
+				{hasCode: true, hasComment: true, comment: " emoji"},
+			},
+		},
+		{
+			name:     "rust lifetimes are not strings",
+			language: "Rust",
+			lines:    []string{`fn f<'a>(x: &'a str) -> &'a str { x } // body`, `// next`},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " body"},
+				{hasComment: true, comment: " next"},
+			},
+		},
+		{
+			name:     "haskell primes are not strings",
+			language: "Haskell",
+			lines:    []string{`let x' = foldl' f z xs -- fold`, `c = '"' -- quote`},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " fold"},
+				{hasCode: true, hasComment: true, comment: " quote"},
+			},
+		},
+		{
+			name:     "shell heredoc with unbalanced quote",
+			language: "Shell",
+			lines:    []string{"cat <<EOF", "Don't panic", "EOF", "# comment one", "# comment two"},
+			want: []want{
+				{hasCode: true},
+				{hasCode: true},
+				{hasCode: true},
+				{hasComment: true, comment: " comment one"},
+				{hasComment: true, comment: " comment two"},
+			},
+		},
+		{
+			name:     "shell quoted heredoc delimiter with trailing comment",
+			language: "Shell",
+			lines:    []string{"cat <<-'END' > out # write", "\t# not a comment", "\tEND", "echo 'it''s' # done"},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " write"},
+				{hasCode: true},
+				{hasCode: true},
+				{hasCode: true, hasComment: true, comment: " done"},
+			},
+		},
+		{
+			name:     "shell shift is not a heredoc",
+			language: "Shell",
+			lines:    []string{"x=$((1<<2)) # shift", "# next"},
+			want: []want{
+				{hasCode: true, hasComment: true, comment: " shift"},
+				{hasComment: true, comment: " next"},
+			},
+		},
+		{
+			name:     "php heredoc",
+			language: "PHP",
+			lines:    []string{"$s = <<<EOT", "Don't // stop", "EOT;", "// comment"},
+			want: []want{
+				{hasCode: true},
+				{hasCode: true},
+				{hasCode: true},
+				{hasComment: true, comment: " comment"},
+			},
+		},
+		{
+			name:     "erlang char literal quote",
+			language: "Erlang",
+			lines:    []string{`Q = $", % quote`, `% next`},
+			want: []want{
+				{hasCode: true},
+				{hasComment: true, comment: " next"},
+			},
+		},
+		{
+			name:     "python triple-quoted string",
+			language: "Python",
+			lines:    []string{`s = """`, `# not a comment`, `"""  # comment`},
+			want: []want{
+				{hasCode: true},
+				{hasCode: true},
+				{hasCode: true, hasComment: true, comment: " comment"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lex := newLexer(CommentPatterns[tt.language])
+			for i, line := range tt.lines {
+				res := lex.scanLine(line)
+				w := tt.want[i]
+				if res.hasCode != w.hasCode || res.hasComment != w.hasComment {
+					t.Errorf("line %d %q: hasCode=%v hasComment=%v, want hasCode=%v hasComment=%v",
+						i+1, line, res.hasCode, res.hasComment, w.hasCode, w.hasComment)
+				}
+				if w.comment != "" && res.comment != w.comment {
+					t.Errorf("line %d %q: comment=%q, want %q", i+1, line, res.comment, w.comment)
+				}
+			}
+		})
+	}
+}
+
+func TestLexerStripped(t *testing.T) {
+	lex := newLexer(CommentPatterns["Go"])
+	res := lex.scanLine(`	x := "a  b" + y // comment`)
+	if want := `x:="a  b"+y`; res.stripped != want {
+		t.Errorf("stripped = %q, want %q", res.stripped, want)
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LicenseUnknown 表示文件头包含版权或许可证声明，但无法识别具体的许可证
+const LicenseUnknown = "Unknown"
+
+// LicenseFile 表示仓库中的许可证文件，如 LICENSE、COPYING
+type LicenseFile struct {
+	Path    string // 文件路径
+	License string // 识别出的许可证，无法识别时为 Unknown
+}
+
+// licenseRule 是通过文本识别许可证的规则，文本需要包含所有短语
+type licenseRule struct {
+	id      string   // SPDX 标识符
+	phrases []string // 小写的短语
+}
+
+// 按顺序匹配的许可证规则，更具体的规则在前
+var licenseRules = []licenseRule{
+	{"AGPL-3.0", []string{"gnu affero general public license"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"LGPL-2.0", []string{"gnu library general public license"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"EPL-2.0", []string{"eclipse public license", "2.0"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"BSD-style", []string{"bsd-style license"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+var (
+	// SPDX 许可证标识符，支持 AND、OR、WITH 组合的表达式
+	spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*(\S+(?:\s+(?:AND|OR|WITH)\s+\S+)*)`)
+	// 许可证文件名，如 LICENSE、LICENSE.md、COPYING、LICENSE-APACHE
+	licenseFilePattern = regexp.MustCompile(`(?i)^(?:un)?licen[cs]e(?:[.-].*)?$|^copying(?:[.-].*)?$`)
+)
+
+// 文件头最多检查的行数
+const maxLicenseHeaderLines = 100
+
+// 许可证文件只检查开头部分，避免匹配到正文中提及的其他许可证
+const maxLicenseFileBytes = 4096
+
+// 判断语言的源代码文件是否需要许可证头，标记语言和数据格式不需要
+func needsLicenseHeader(language string) bool {
+	_, hasStyle := CommentPatterns[language]
+	return hasStyle && !slices.Contains(markupLanguages, language)
+}
+
+// 从文件开头的注释块中识别许可证，没有许可证头时返回空
+// 注释块从文件开头开始，跳过空行和 shebang，遇到第一行代码时结束
+func detectLicenseHeader(content, language string) string {
+	lex := newLexer(CommentPatterns[language])
+	var header []string
+	for i, line := range strings.Split(content, "\n") {
+		if i >= maxLicenseHeaderLines {
+			break
+		}
+		if i == 0 && strings.HasPrefix(line, "#!") {
+			continue
+		}
+		res := lex.scanLine(line)
+		if res.hasCode {
+			break
+		}
+		// 去除块注释每行开头的 * 等装饰字符
+		if text := strings.TrimLeft(res.comment, " \t*#!/;-"); text != "" {
+			header = append(header, text)
+		}
+	}
+	if len(header) == 0 {
+		return ""
+	}
+
+	text := strings.Join(header, " ")
+	if m := spdxPattern.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	if license := matchLicenseText(text); license != "" {
+		return license
+	}
+	lower := strings.ToLower(text)
+	if strings.Contains(lower, "copyright") || strings.Contains(lower, "license") || strings.Contains(lower, "licence") {
+		return LicenseUnknown
+	}
+	return ""
+}
+
+// 根据许可证正文识别许可证，无法识别时返回空
+func matchLicenseText(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	for _, rule := range licenseRules {
+		matched := true
+		for _, phrase := range rule.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule.id
+		}
+	}
+	return ""
+}
+
+// 判断文件名是否是许可证文件
+func isLicenseFile(name string) bool {
+	return licenseFilePattern.MatchString(name)
+}
+
+// 识别许可证文件的许可证
+func analyzeLicenseFile(path string) (*LicenseFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content = content[:min(len(content), maxLicenseFileBytes)]
+
+	res := &LicenseFile{Path: path, License: LicenseUnknown}
+	if m := spdxPattern.FindSubmatch(content); m != nil {
+		res.License = string(m[1])
+	} else if license := matchLicenseText(string(content)); license != "" {
+		res.License = license
+	}
+	return res, nil
+}
+
+// 按目录深度和路径排列许可证文件，仓库根目录的许可证文件在前
+func sortLicenseFiles(files []*LicenseFile) {
+	slices.SortFunc(files, func(a, b *LicenseFile) int {
+		da := strings.Count(filepath.ToSlash(a.Path), "/")
+		db := strings.Count(filepath.ToSlash(b.Path), "/")
+		if da != db {
+			return da - db
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+}
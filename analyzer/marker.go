@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认识别的注释标记
+var defaultCommentMarkers = []string{"TODO", "FIXME", "HACK", "XXX"}
+
+// CommentMarker 表示注释中的一个标记，如 TODO(alice): 重构
+type CommentMarker struct {
+	Tag      string // 标记名，如 TODO
+	Owner    string // 括号中的负责人，如 TODO(alice) 中的 alice，没有时为空
+	Text     string // 标记之后的说明
+	Path     string // 文件路径
+	Line     int    // 行号
+	Language string // 所在区域的语言
+
+	Author string    // 最后修改该行的作者，通过 git blame 获取
+	Date   time.Time // 最后修改该行的时间，未提交或不是 Git 仓库时为零值
+}
+
+// 按标记列表编译的正则表达式缓存
+var markerPatterns sync.Map
+
+// 获取匹配注释标记的正则表达式，标记只匹配完整的单词，可以跟随 (负责人) 和冒号
+// 忽略空的标记，没有有效标记时返回 nil
+func markerPattern(tags []string) *regexp.Regexp {
+	key := strings.Join(tags, "\x00")
+	if re, ok := markerPatterns.Load(key); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	var quoted []string
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			quoted = append(quoted, regexp.QuoteMeta(tag))
+		}
+	}
+	if len(quoted) == 0 {
+		markerPatterns.Store(key, (*regexp.Regexp)(nil))
+		return nil
+	}
+	re := regexp.MustCompile(`(?:^|[^\w])(` + strings.Join(quoted, "|") + `)(?:\(([^)]*)\))?(?:[^\w(]|$)\s*(.*)`)
+	markerPatterns.Store(key, re)
+	return re
+}
+
+// 在注释内容中查找标记，每行只记录第一个标记
+func findMarker(comment string, tags []string) *CommentMarker {
+	re := markerPattern(tags)
+	if re == nil {
+		return nil
+	}
+	m := re.FindStringSubmatch(comment)
+	if m == nil {
+		return nil
+	}
+	return &CommentMarker{
+		Tag:   m[1],
+		Owner: strings.TrimSpace(m[2]),
+		Text:  strings.TrimSpace(strings.TrimLeft(m[3], ":- ")),
+	}
+}
@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// 压缩或打包文件的常见文件名模式
+var minifiedFilePatterns = []string{
+	"*.min.js", "*.min.mjs", "*.min.css",
+	"*.bundle.js", "*-bundle.js", "*.bundle.css",
+	"*.chunk.js", "*.chunk.css",
+}
+
+// 打包工具生成的运行时标记
+var bundleMarkers = [][]byte{
+	[]byte("__webpack_require__"),
+	[]byte("webpackBootstrap"),
+	[]byte("parcelRequire"),
+}
+
+// 参与内容启发式判断的语言
+var minifiableLanguages = []string{"JavaScript", "TypeScript", "CSS", "JSON", "HTML"}
+
+const (
+	minifiedAvgLineLength  = 300  // 平均行长度超过该值视为压缩文件
+	minifiedMaxLineLength  = 500  // 存在超过该长度的行，且空白比例过低时视为压缩文件
+	minifiedWhitespaceRate = 0.05 // 空白字符比例阈值
+)
+
+// 判断文件是否是压缩或打包后的文件，需要在行统计完成后调用
+func (f *FileStats) isMinified(content []byte) bool {
+	name := strings.ToLower(filepath.Base(f.Path))
+	for _, pattern := range minifiedFilePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	if !slices.Contains(minifiableLanguages, f.Language) {
+		return false
+	}
+	for _, marker := range bundleMarkers {
+		if bytes.Contains(content, marker) {
+			return true
+		}
+	}
+
+	if f.TotalLines == 0 {
+		return false
+	}
+	if f.AvgLineLength > minifiedAvgLineLength {
+		return true
+	}
+	return f.MaxLineLength > minifiedMaxLineLength && whitespaceRate(content) < minifiedWhitespaceRate
+}
+
+// 计算空白字符占比
+func whitespaceRate(content []byte) float64 {
+	if len(content) == 0 {
+		return 0
+	}
+	var n int
+	for _, c := range content {
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			n++
+		}
+	}
+	return float64(n) / float64(len(content))
+}
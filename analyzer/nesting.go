@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+)
+
+// nestingTracker 计算每行代码的嵌套深度
+type nestingTracker interface {
+	// 返回该行的嵌套深度，不是代码行时返回 -1，inString 表示行首处于跨行字符串中
+	depth(line string, res lineResult, inString bool) int
+}
+
+// 按缩进层级计算嵌套深度的语言
+var indentNestingLanguages = []string{"Python", "Starlark", "YAML"}
+
+// 创建语言对应的嵌套深度计算器，不支持的语言返回 nil
+// C 系语言按花括号深度计算，Python 和 YAML 按缩进层级计算
+func newNestingTracker(language string) nestingTracker {
+	switch {
+	case language == "Go" || slices.Contains(braceFunctionLanguages, language):
+		return &braceNesting{}
+	case slices.Contains(indentNestingLanguages, language):
+		return &indentNesting{}
+	}
+	return nil
+}
+
+// braceNesting 按花括号深度计算嵌套深度
+type braceNesting struct {
+	level int // 当前花括号深度
+}
+
+func (n *braceNesting) depth(_ string, res lineResult, _ bool) int {
+	// 以右花括号开始的行与对应的左花括号所在行深度相同
+	code := strings.TrimSpace(res.code)
+	depth := n.level - (len(code) - len(strings.TrimLeft(code, "}")))
+
+	n.level += strings.Count(res.code, "{") - strings.Count(res.code, "}")
+	n.level = max(n.level, 0)
+
+	if !res.hasCode {
+		return -1
+	}
+	return max(depth, 0)
+}
+
+// indentNesting 按缩进层级计算嵌套深度
+type indentNesting struct {
+	indents []int // 各层级的缩进宽度
+}
+
+func (n *indentNesting) depth(line string, res lineResult, inString bool) int {
+	// 跨行字符串中的行不影响缩进层级
+	if !res.hasCode || inString {
+		return -1
+	}
+
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	for len(n.indents) > 0 && n.indents[len(n.indents)-1] > indent {
+		n.indents = n.indents[:len(n.indents)-1]
+	}
+	if len(n.indents) == 0 || n.indents[len(n.indents)-1] < indent {
+		n.indents = append(n.indents, indent)
+	}
+	return len(n.indents) - 1
+}
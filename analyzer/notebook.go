@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// NotebookLanguage 是 Jupyter Notebook 文件的语言名称
+const NotebookLanguage = "Jupyter Notebook"
+
+// NotebookStats 存储 Jupyter Notebook 的单元格统计
+type NotebookStats struct {
+	Kernel        string // 内核语言，代码单元格按该语言统计
+	CodeCells     int    // 代码单元格数
+	MarkdownCells int    // Markdown 单元格数，按文档统计
+	RawCells      int    // 原始单元格数，不统计行数
+}
+
+// notebook 是 .ipynb 文件中与统计相关的部分，单元格的输出不会被解析
+type notebook struct {
+	Cells      []notebookCell `json:"cells"`
+	Worksheets []struct {
+		Cells []notebookCell `json:"cells"`
+	} `json:"worksheets"` // nbformat 3 的单元格位于 worksheets 中
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType string         `json:"cell_type"`
+	Source   notebookSource `json:"source"`
+	Input    notebookSource `json:"input"` // nbformat 3 代码单元格的源码
+}
+
+// notebookSource 是单元格的源码，可以是字符串或字符串数组
+type notebookSource string
+
+func (s *notebookSource) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*s = notebookSource(strings.Join(lines, ""))
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*s = notebookSource(text)
+	return nil
+}
+
+// 分析 Jupyter Notebook，代码单元格按内核语言统计，Markdown 单元格按文档统计，忽略输出
+func (f *FileStats) analyzeNotebook(content []byte, options FileAnalyzerOptions) error {
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil {
+		// 无法解析时按普通 JSON 文件统计
+		PrintWarning("无法解析 Notebook: %s (%v)", f.Path, err)
+		f.Language = "JSON"
+		return f.analyzeFile(content, options)
+	}
+
+	cells := nb.Cells
+	for _, worksheet := range nb.Worksheets {
+		cells = append(cells, worksheet.Cells...)
+	}
+
+	f.Notebook = &NotebookStats{Kernel: notebookKernel(&nb)}
+	counters := make(map[string]*lineCounter)
+	lineNo := 0
+	for _, cell := range cells {
+		var counter *lineCounter
+		source := string(cell.Source)
+
+		switch cell.CellType {
+		case "code":
+			f.Notebook.CodeCells++
+			if source == "" {
+				source = string(cell.Input)
+			}
+			if counter = counters[f.Notebook.Kernel]; counter == nil {
+				counter = newLineCounter(f.Notebook.Kernel)
+				counters[f.Notebook.Kernel] = counter
+			}
+		case "markdown", "heading":
+			f.Notebook.MarkdownCells++
+			if counter = counters[NotebookLanguage]; counter == nil {
+				counter = newProseCounter(NotebookLanguage)
+				counters[NotebookLanguage] = counter
+			}
+		default:
+			f.Notebook.RawCells++
+			continue
+		}
+
+		// 每个单元格都是独立的代码块
+		counter.reset()
+		if source == "" {
+			continue
+		}
+		// 行号按所有单元格的源码依次排列计算
+		for _, line := range strings.Split(strings.TrimSuffix(source, "\n"), "\n") {
+			lineNo++
+			counter.count(strings.TrimSuffix(line, "\r"), lineNo, options)
+		}
+	}
+
+	f.mergeCounters(counters)
+	return nil
+}
+
+// 获取 Notebook 的内核语言，未声明或无法识别时默认为 Python
+// 只接受已知的语言名称，元数据中的任意字符串不会进入报告
+func notebookKernel(nb *notebook) string {
+	for _, name := range []string{nb.Metadata.LanguageInfo.Name, nb.Metadata.KernelSpec.Language} {
+		if lang := LookupLanguage(name); lang != "" {
+			return lang
+		}
+	}
+	return "Python"
+}
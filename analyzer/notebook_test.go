@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzeNotebook(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantKernel string
+	}{
+		{
+			name:       "known kernel",
+			content:    `{"metadata":{"language_info":{"name":"r"}},"cells":[{"cell_type":"markdown","source":["# Title\n"]},{"cell_type":"code","source":["x <- 1 # one\n"]}]}`,
+			wantKernel: "R",
+		},
+		{
+			name:       "unknown language_info falls back to kernelspec",
+			content:    `{"metadata":{"language_info":{"name":"\"</script><script>alert(1)//"},"kernelspec":{"language":"julia"}},"cells":[{"cell_type":"code","source":"x = 1\n"}]}`,
+			wantKernel: "Julia",
+		},
+		{
+			name:       "unknown kernel defaults to python",
+			content:    `{"metadata":{"kernelspec":{"language":"<b>weird</b>"}},"cells":[{"cell_type":"markdown","source":"only prose\n"},{"cell_type":"code","source":"x = 1\n"}]}`,
+			wantKernel: "Python",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FileStats{Stat: &Stat{TotalFiles: 1}, Path: "a.ipynb", Language: NotebookLanguage}
+			if err := f.analyzeNotebook([]byte(tt.content), DefaultFileOptions()); err != nil {
+				t.Fatal(err)
+			}
+			if f.Notebook.Kernel != tt.wantKernel {
+				t.Errorf("Kernel = %q, want %q", f.Notebook.Kernel, tt.wantKernel)
+			}
+			for lang, region := range f.Regions {
+				region.CalculateAvg()
+				if math.IsNaN(region.CommentRatio) || math.IsNaN(region.ComplexityDensity) {
+					t.Errorf("region %s: CommentRatio=%v ComplexityDensity=%v", lang, region.CommentRatio, region.ComplexityDensity)
+				}
+			}
+		})
+	}
+}
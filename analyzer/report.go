@@ -26,6 +26,30 @@ type ReportData struct {
 	SortedExts     []ExtensionItem
 	FilesBySize    []*FileStats
 	FilesByLines   []*FileStats
+	MinifiedFiles  []*FileStats // 压缩或打包后的文件
+
+	ComplexFunctions []FunctionItem        // 按圈复杂度排序的函数
+	FilesByNesting   []*FileStats          // 按最大嵌套深度排序的文件
+	CloneGroups      []*CloneGroup         // 最大的重复代码组
+	DuplicateFiles   []*DuplicateFileGroup // 浪费空间最多的重复文件组
+
+	// 注释标记数据
+	MarkerTags []CountItem // 按数量排序的标记名
+	MarkerDirs []CountItem // 按数量排序的目录
+
+	// 许可证数据
+	Licenses []CountItem // 按文件数排序的许可证
+
+	// 测试代码数据
+	TestDirs []TestDirItem // 按代码行数排序的顶层目录
+
+	// 二进制文件数据
+	SortedBinaryExts []BinaryItem // 按总大小排序的二进制文件扩展名
+	BinaryFiles      int          // 二进制文件总数
+	BinarySize       int64        // 二进制文件总大小
+
+	// Go 代码数据
+	GoPackages []*GoPackageStats // 按目录和包名排序的 Go 包
 
 	// Git 相关数据
 	HasGitStats       bool                      // 是否有 Git 统计信息
@@ -77,6 +101,45 @@ type ExtensionItem struct {
 	Stats *ExtensionStats
 }
 
+// FunctionItem 表示UI显示用的函数项
+type FunctionItem struct {
+	Path string
+	*FunctionStats
+}
+
+// TestDirItem 表示UI显示用的目录测试代码统计
+type TestDirItem struct {
+	Name string
+	TestSplit
+}
+
+// CountItem 表示UI显示用的计数项
+type CountItem struct {
+	Name  string
+	Count int
+}
+
+// 将计数按数量降序排列，数量相同时按名称排列
+func sortedCounts(counts map[string]int) []CountItem {
+	items := make([]CountItem, 0, len(counts))
+	for name, count := range counts {
+		items = append(items, CountItem{name, count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// BinaryItem 表示UI显示用的二进制文件扩展名项
+type BinaryItem struct {
+	Name  string
+	Stats *BinaryStats
+}
+
 //go:embed report.tpl
 var htmlReportTemplate []byte
 
@@ -122,6 +185,12 @@ func GenerateHTMLReport(data ReportData) string {
 			}
 			return t.Format("2006-01-02")
 		},
+		"daysSince": func(t time.Time) int {
+			if t.IsZero() {
+				return -1
+			}
+			return int(time.Since(t).Hours() / 24)
+		},
 	}
 
 	// 确保GenerationTime字段已设置
@@ -161,6 +230,76 @@ func GenerateHTMLReport(data ReportData) string {
 		data.SortedExts = exts
 	}
 
+	// 处理二进制文件数据
+	if len(stats.BinaryStats) > 0 {
+		binaries := make([]BinaryItem, 0, len(stats.BinaryStats))
+		for ext, stat := range stats.BinaryStats {
+			data.BinaryFiles += stat.TotalFiles
+			data.BinarySize += stat.TotalSize
+			if ext == "" {
+				ext = "(无扩展名)"
+			}
+			binaries = append(binaries, BinaryItem{ext, stat})
+		}
+		// 按总大小排序
+		sort.Slice(binaries, func(i, j int) bool {
+			return binaries[i].Stats.TotalSize > binaries[j].Stats.TotalSize
+		})
+		data.SortedBinaryExts = binaries
+	}
+
+	// 处理重复代码数据
+	if groups := stats.Duplication.CloneGroups; len(groups) > 0 {
+		data.CloneGroups = groups[:min(max(data.TopN, 1), len(groups))]
+	}
+
+	// 处理重复文件数据
+	if groups := stats.DuplicateFiles.Groups; len(groups) > 0 {
+		data.DuplicateFiles = groups[:min(max(data.TopN, 1), len(groups))]
+	}
+
+	// 处理注释标记数据
+	data.MarkerTags = sortedCounts(stats.MarkerTags)
+	if dirs := sortedCounts(stats.MarkerDirs); len(dirs) > 0 {
+		data.MarkerDirs = dirs[:min(max(data.TopN, 1), len(dirs))]
+	}
+
+	// 处理许可证数据
+	data.Licenses = sortedCounts(stats.LicenseStats)
+
+	// 处理测试代码数据
+	for dir, split := range stats.TestDirs {
+		data.TestDirs = append(data.TestDirs, TestDirItem{dir, split})
+	}
+	sort.Slice(data.TestDirs, func(i, j int) bool {
+		a, b := data.TestDirs[i], data.TestDirs[j]
+		if la, lb := a.TestStats.CodeLines+a.ProductionStats.CodeLines, b.TestStats.CodeLines+b.ProductionStats.CodeLines; la != lb {
+			return la > lb
+		}
+		return a.Name < b.Name
+	})
+
+	// 处理 Go 包数据
+	for _, pkg := range stats.GoPackages {
+		data.GoPackages = append(data.GoPackages, pkg)
+	}
+	sort.Slice(data.GoPackages, func(i, j int) bool {
+		if data.GoPackages[i].Dir != data.GoPackages[j].Dir {
+			return data.GoPackages[i].Dir < data.GoPackages[j].Dir
+		}
+		return data.GoPackages[i].Name < data.GoPackages[j].Name
+	})
+
+	// 处理压缩文件数据
+	for _, fs := range stats.FileStats {
+		if fs.IsMinified {
+			data.MinifiedFiles = append(data.MinifiedFiles, fs)
+		}
+	}
+	sort.Slice(data.MinifiedFiles, func(i, j int) bool {
+		return data.MinifiedFiles[i].TotalSize > data.MinifiedFiles[j].TotalSize
+	})
+
 	// 处理文件数据
 	if len(stats.FileStats) > 0 {
 		// 使用用户指定的TopN值
@@ -194,6 +333,39 @@ func GenerateHTMLReport(data ReportData) string {
 		}
 		data.FilesByLines = filesByLines[:limit]
 		data.FileLinesLimit = limit
+
+		// 按最大嵌套深度排序的文件，只包含计算了嵌套深度的文件
+		var filesByNesting []*FileStats
+		for _, fs := range stats.FileStats {
+			if fs.NestedLines > 0 {
+				filesByNesting = append(filesByNesting, fs)
+			}
+		}
+		sort.Slice(filesByNesting, func(i, j int) bool {
+			if filesByNesting[i].MaxNestingDepth != filesByNesting[j].MaxNestingDepth {
+				return filesByNesting[i].MaxNestingDepth > filesByNesting[j].MaxNestingDepth
+			}
+			return filesByNesting[i].AvgNestingDepth > filesByNesting[j].AvgNestingDepth
+		})
+		data.FilesByNesting = filesByNesting[:min(topN, len(filesByNesting))]
+
+		// 按复杂度排序的函数
+		var functions []FunctionItem
+		for _, fs := range stats.FileStats {
+			for _, fn := range fs.Functions {
+				// 只有通过语法树分析的函数才有复杂度
+				if fn.Cyclomatic > 0 {
+					functions = append(functions, FunctionItem{fs.Path, fn})
+				}
+			}
+		}
+		sort.Slice(functions, func(i, j int) bool {
+			if functions[i].Cyclomatic != functions[j].Cyclomatic {
+				return functions[i].Cyclomatic > functions[j].Cyclomatic
+			}
+			return functions[i].Cognitive > functions[j].Cognitive
+		})
+		data.ComplexFunctions = functions[:min(topN, len(functions))]
 	}
 
 	// 处理 Git 数据
@@ -303,6 +303,11 @@
             margin-top: 20px;
         }
         
+        .file-details .region-table {
+            width: 100%;
+            margin-bottom: 15px;
+        }
+        
         .file-details .metric-box {
             background-color: #f8f9fa;
             border-radius: 4px;
@@ -535,6 +540,39 @@
         <div class="nav-item" data-target="section-extensions">扩展名统计</div>
         <div class="nav-item" data-target="section-files-size">最大文件</div>
         <div class="nav-item" data-target="section-files-lines">最长文件</div>
+        {{if .ComplexFunctions}}
+        <div class="nav-item" data-target="section-functions">复杂函数</div>
+        {{end}}
+        {{if .FilesByNesting}}
+        <div class="nav-item" data-target="section-nesting">嵌套最深</div>
+        {{end}}
+        {{if .CloneGroups}}
+        <div class="nav-item" data-target="section-duplication">重复代码</div>
+        {{end}}
+        {{if .DuplicateFiles}}
+        <div class="nav-item" data-target="section-duplicate-files">重复文件</div>
+        {{end}}
+        {{if .Stats.Markers}}
+        <div class="nav-item" data-target="section-markers">代码标记</div>
+        {{end}}
+        {{if .TestDirs}}
+        <div class="nav-item" data-target="section-tests">测试代码</div>
+        {{end}}
+        {{if or .Licenses .Stats.MissingLicense .Stats.LicenseFiles}}
+        <div class="nav-item" data-target="section-licenses">许可证</div>
+        {{end}}
+        {{if .MinifiedFiles}}
+        <div class="nav-item" data-target="section-minified">压缩文件</div>
+        {{end}}
+        {{if .Stats.UndecodableFiles}}
+        <div class="nav-item" data-target="section-undecodable">无法解码</div>
+        {{end}}
+        {{if .SortedBinaryExts}}
+        <div class="nav-item" data-target="section-binaries">二进制文件</div>
+        {{end}}
+        {{if .GoPackages}}
+        <div class="nav-item" data-target="section-go">Go 代码</div>
+        {{end}}
         {{if .HasGitStats}}
         <div class="nav-item" data-target="section-git-stats">Git 统计</div>
         <div class="nav-item" data-target="section-contributors">贡献者看板</div>
@@ -549,10 +587,25 @@
             <div class="summary-item"><span class="summary-label">总代码量:</span> {{.Stats.TotalLines}} 行 ({{printf "%.2f" (divideBy .Stats.TotalSize 1048576)}} MB)</div>
             <div class="summary-item"><span class="summary-label">代码行数:</span> {{.Stats.CodeLines}} 行 ({{printf "%.1f%%" (multiply .Stats.CodeDensity 100)}})</div>
             <div class="summary-item"><span class="summary-label">注释行数:</span> {{.Stats.CommentLines}} 行 ({{printf "%.1f%%" (multiply .Stats.CommentDensity 100)}})</div>
+            <div class="summary-item"><span class="summary-label">文档行数:</span> {{.Stats.DocLines}} 行 ({{printf "%.1f%%" (multiply (divideBy .Stats.DocLines .Stats.TotalLines) 100)}})</div>
             <div class="summary-item"><span class="summary-label">空白行数:</span> {{.Stats.BlankLines}} 行 ({{printf "%.1f%%" (multiply .Stats.AvgBlankLines 100)}})</div>
+            <div class="summary-item"><span class="summary-label">重复代码:</span> {{.Stats.Duplication.DuplicatedLines}} 行 ({{printf "%.1f%%" (multiply .Stats.Duplication.Ratio 100)}})</div>
+            {{if .Stats.DuplicateFiles.Groups}}
+            <div class="summary-item"><span class="summary-label">重复文件:</span> {{.Stats.DuplicateFiles.Files}} 个多余副本, {{.Stats.DuplicateFiles.WastedLines}} 行 ({{printf "%.2f" (divideBy .Stats.DuplicateFiles.WastedBytes 1024)}} KB){{if .Stats.DuplicateFiles.Skipped}}，已从统计中排除{{end}}</div>
+            {{end}}
+            <div class="summary-item"><span class="summary-label">代码标记:</span> {{.Stats.Stat.Markers}} 个{{range .MarkerTags}} · {{.Name}} {{.Count}}{{end}}</div>
+            <div class="summary-item"><span class="summary-label">许可证:</span> {{range $i, $file := .Stats.LicenseFiles}}{{if $i}}, {{end}}{{$file.License}}{{else}}未找到许可证文件{{end}}{{if .Stats.MissingLicense}}，{{len .Stats.MissingLicense}} 个源代码文件缺少许可证头{{end}}</div>
+            <div class="summary-item"><span class="summary-label">测试代码:</span> {{.Stats.TestStats.TotalFiles}} 个文件, {{.Stats.TestStats.CodeLines}} 行代码 (测试比 {{printf "%.2f" .Stats.TestRatio}})</div>
+            <div class="summary-item"><span class="summary-label">混合行数:</span> {{.Stats.MixedLines}} 行 (同时包含代码与注释)</div>
+            <div class="summary-item"><span class="summary-label">手写代码:</span> {{.Stats.HandwrittenStats.TotalFiles}} 个文件, {{.Stats.HandwrittenStats.CodeLines}} 行代码</div>
+            <div class="summary-item"><span class="summary-label">生成代码:</span> {{.Stats.GeneratedStats.TotalFiles}} 个文件, {{.Stats.GeneratedStats.CodeLines}} 行代码</div>
+            <div class="summary-item"><span class="summary-label">压缩文件:</span> {{.Stats.MinifiedStats.TotalFiles}} 个文件, {{.Stats.MinifiedStats.CodeLines}} 行代码 ({{printf "%.2f" (divideBy .Stats.MinifiedStats.TotalSize 1024)}} KB)</div>
+            <div class="summary-item"><span class="summary-label">文件编码:</span> {{range $encoding, $count := .Stats.EncodingStats}}{{$encoding}} {{$count}} 个; {{end}}无法解码 {{len .Stats.UndecodableFiles}} 个</div>
+            <div class="summary-item"><span class="summary-label">二进制文件:</span> {{.BinaryFiles}} 个文件 ({{printf "%.2f" (divideBy .BinarySize 1048576)}} MB, 不计入行数统计)</div>
             <div class="summary-item"><span class="summary-label">注释比例:</span> {{printf "%.2f" .Stats.CommentRatio}} (注释行/代码行)</div>
             <div class="summary-item"><span class="summary-label">平均文件大小:</span> {{printf "%.2f" (divideBy .Stats.AvgFileSize 1024)}} KB</div>
             <div class="summary-item"><span class="summary-label">平均行长度:</span> {{printf "%.1f" .Stats.AvgLineLength}} 字符/行</div>
+            <div class="summary-item"><span class="summary-label">最长行:</span> {{.Stats.MaxLineLength}} 字符, 超长行 {{.Stats.LongLines}} 行</div>
         </div>
 
         <!-- 可视化图表 -->
@@ -707,8 +760,15 @@
                     <th>文件数</th>
                     <th>代码行</th>
                     <th>注释行</th>
+                    <th>文档行</th>
                     <th>空白行</th>
+                    <th>混合行</th>
                     <th>注释比例</th>
+                    <th>复杂度</th>
+                    <th>复杂度密度</th>
+                    <th>标记数</th>
+                    <th>测试代码行</th>
+                    <th>测试比</th>
                     <th>平均行长度</th>
                 </tr>
             </thead>
@@ -720,14 +780,56 @@
                     <td>{{.Stats.TotalFiles}}</td>
                     <td>{{.Stats.CodeLines}}</td>
                     <td>{{.Stats.CommentLines}}</td>
+                    <td>{{.Stats.DocLines}}</td>
                     <td>{{.Stats.BlankLines}}</td>
+                    <td>{{.Stats.MixedLines}}</td>
                     <td>{{printf "%.2f" .Stats.CommentRatio}}</td>
+                    <td>{{.Stats.Complexity}}</td>
+                    <td>{{printf "%.3f" .Stats.ComplexityDensity}}</td>
+                    <td>{{.Stats.Markers}}</td>
+                    <td>{{.Stats.TestStats.CodeLines}}</td>
+                    <td>{{printf "%.2f" .Stats.TestRatio}}</td>
                     <td>{{printf "%.1f" .Stats.AvgLineLength}}</td>
                 </tr>
                 {{end}}
                 {{end}}
             </tbody>
         </table>
+
+        <!-- 函数长度分布 -->
+        {{if gt .Stats.FunctionCount 0}}
+        <h3>函数长度分布</h3>
+        <table id="function-length-table" class="display">
+            <thead>
+                <tr>
+                    <th>语言</th>
+                    <th>函数数</th>
+                    <th>平均长度</th>
+                    <th>最长</th>
+                    <th>1-50 行</th>
+                    <th>51-100 行</th>
+                    <th>101-200 行</th>
+                    <th>200 行以上</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .TopLanguages}}
+                {{if gt .Stats.FunctionCount 0}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Stats.FunctionCount}}</td>
+                    <td>{{printf "%.1f" .Stats.AvgFunctionLength}}</td>
+                    <td>{{.Stats.MaxFunctionLength}}</td>
+                    <td>{{subtract .Stats.FunctionCount .Stats.FunctionsOver50}}</td>
+                    <td>{{subtract .Stats.FunctionsOver50 .Stats.FunctionsOver100}}</td>
+                    <td>{{subtract .Stats.FunctionsOver100 .Stats.FunctionsOver200}}</td>
+                    <td>{{.Stats.FunctionsOver200}}</td>
+                </tr>
+                {{end}}
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
         {{end}}
     </div>
 
@@ -796,8 +898,15 @@
                     <th>文件路径</th>
                     <th>代码行</th>
                     <th>注释行</th>
+                    <th>文档行</th>
                     <th>空白行</th>
                     <th>注释比例</th>
+                    <th>复杂度</th>
+                    <th>复杂度密度</th>
+                    <th>函数数</th>
+                    <th>最长函数</th>
+                    <th>最长行</th>
+                    <th>超长行</th>
                 </tr>
             </thead>
             <tbody>
@@ -806,8 +915,15 @@
                     <td>{{.Path}}</td>
                     <td>{{.CodeLines}}</td>
                     <td>{{.CommentLines}}</td>
+                    <td>{{.DocLines}}</td>
                     <td>{{.BlankLines}}</td>
                     <td>{{printf "%.2f" (commentRatio .CommentLines .CodeLines)}}</td>
+                    <td>{{.Complexity}}</td>
+                    <td>{{printf "%.3f" .ComplexityDensity}}</td>
+                    <td>{{.FunctionCount}}</td>
+                    <td>{{.MaxFunctionLength}}</td>
+                    <td>{{.MaxLineLength}}</td>
+                    <td>{{.LongLines}}</td>
                 </tr>
                 {{end}}
             </tbody>
@@ -815,6 +931,432 @@
         {{end}}
     </div>
 
+    <!-- 按复杂度排序的函数区域 -->
+    {{if .ComplexFunctions}}
+    <div id="section-functions" class="section">
+        <table id="functions-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件路径</th>
+                    <th>函数</th>
+                    <th>行号</th>
+                    <th>行数</th>
+                    <th>圈复杂度</th>
+                    <th>认知复杂度</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .ComplexFunctions}}
+                <tr>
+                    <td>{{.Path}}</td>
+                    <td>{{.Name}}</td>
+                    <td>{{.Line}}</td>
+                    <td>{{.Lines}}</td>
+                    <td>{{.Cyclomatic}}</td>
+                    <td>{{.Cognitive}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 按嵌套深度排序的文件区域 -->
+    {{if .FilesByNesting}}
+    <div id="section-nesting" class="section">
+        <table id="nesting-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件路径</th>
+                    <th>语言</th>
+                    <th>最大嵌套深度</th>
+                    <th>平均嵌套深度</th>
+                    <th>代码行</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .FilesByNesting}}
+                <tr>
+                    <td>{{.Path}}</td>
+                    <td>{{.Language}}</td>
+                    <td>{{.MaxNestingDepth}}</td>
+                    <td>{{printf "%.2f" .AvgNestingDepth}}</td>
+                    <td>{{.CodeLines}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 重复代码区域 -->
+    {{if .CloneGroups}}
+    <div id="section-duplication" class="section">
+        <div class="summary">
+            <h3>重复代码</h3>
+            <p>去除空白和注释后，连续 {{.Stats.Duplication.Window}} 行以上相同的代码视为重复</p>
+            <div class="summary-item"><span class="summary-label">重复行数:</span> {{.Stats.Duplication.DuplicatedLines}} / {{.Stats.Duplication.CodeLines}} 行 ({{printf "%.1f%%" (multiply .Stats.Duplication.Ratio 100)}})</div>
+            <div class="summary-item"><span class="summary-label">重复代码组:</span> {{len .Stats.Duplication.CloneGroups}} 组</div>
+        </div>
+        <table id="duplication-table" class="display">
+            <thead>
+                <tr>
+                    <th>行数</th>
+                    <th>出现次数</th>
+                    <th>位置</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .CloneGroups}}
+                <tr>
+                    <td>{{.Lines}}</td>
+                    <td>{{len .Locations}}</td>
+                    <td>{{range $i, $loc := .Locations}}{{if $i}}<br>{{end}}{{$loc.Path}}:{{$loc.StartLine}}-{{$loc.EndLine}}{{end}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 重复文件区域 -->
+    {{if .DuplicateFiles}}
+    <div id="section-duplicate-files" class="section">
+        <div class="summary">
+            <h3>重复文件</h3>
+            <p>内容完全相同的文件，每组第一个文件之外的副本计为浪费{{if .Stats.DuplicateFiles.Skipped}}，副本已从统计中排除{{end}}</p>
+            <div class="summary-item"><span class="summary-label">重复文件组:</span> {{len .Stats.DuplicateFiles.Groups}} 组, {{.Stats.DuplicateFiles.Files}} 个多余副本</div>
+            <div class="summary-item"><span class="summary-label">浪费:</span> {{.Stats.DuplicateFiles.WastedLines}} 行, {{printf "%.2f" (divideBy .Stats.DuplicateFiles.WastedBytes 1024)}} KB</div>
+        </div>
+        <table id="duplicate-files-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件数</th>
+                    <th>行数</th>
+                    <th>大小(KB)</th>
+                    <th>浪费行数</th>
+                    <th>浪费大小(KB)</th>
+                    <th>文件</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .DuplicateFiles}}
+                <tr>
+                    <td>{{len .Paths}}</td>
+                    <td>{{.Lines}}</td>
+                    <td>{{printf "%.2f" (divideBy .Size 1024)}}</td>
+                    <td>{{.WastedLines}}</td>
+                    <td>{{printf "%.2f" (divideBy .WastedBytes 1024)}}</td>
+                    <td>{{range $i, $path := .Paths}}{{if $i}}<br>{{end}}{{$path}}{{end}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 代码标记区域 -->
+    {{if .Stats.Markers}}
+    <div id="section-markers" class="section">
+        <div class="summary">
+            <h3>代码标记</h3>
+            <p>注释中的 {{range $i, $tag := .MarkerTags}}{{if $i}}、{{end}}{{$tag.Name}}{{end}} 等标记{{if .HasGitStats}}，时间为通过 git blame 获取的最后修改时间{{end}}</p>
+            {{range .MarkerTags}}
+            <div class="summary-item"><span class="summary-label">{{.Name}}:</span> {{.Count}} 个</div>
+            {{end}}
+        </div>
+        <h4>标记最多的目录</h4>
+        <table id="marker-dirs-table" class="display">
+            <thead>
+                <tr>
+                    <th>目录</th>
+                    <th>标记数</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .MarkerDirs}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Count}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        <h4>所有标记</h4>
+        <table id="markers-table" class="display">
+            <thead>
+                <tr>
+                    <th>标记</th>
+                    <th>负责人</th>
+                    <th>说明</th>
+                    <th>文件</th>
+                    <th>行号</th>
+                    <th>作者</th>
+                    <th>修改日期</th>
+                    <th>天数</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Stats.Markers}}
+                <tr>
+                    <td>{{html .Tag}}</td>
+                    <td>{{html .Owner}}</td>
+                    <td>{{html .Text}}</td>
+                    <td>{{html .Path}}</td>
+                    <td>{{.Line}}</td>
+                    <td>{{html .Author}}</td>
+                    <td>{{formatDate .Date}}</td>
+                    <td>{{$days := daysSince .Date}}{{if ge $days 0}}{{$days}}{{end}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 测试代码区域 -->
+    {{if .TestDirs}}
+    <div id="section-tests" class="section">
+        <div class="summary">
+            <h3>测试代码</h3>
+            <p>根据文件名（如 _test.go、test_*.py、*.spec.ts）和目录名（如 test、tests、__tests__）识别测试代码，测试比为测试代码行数与非测试代码行数之比</p>
+            <div class="summary-item"><span class="summary-label">测试代码:</span> {{.Stats.TestStats.TotalFiles}} 个文件, {{.Stats.TestStats.CodeLines}} 行代码</div>
+            <div class="summary-item"><span class="summary-label">非测试代码:</span> {{.Stats.ProductionStats.TotalFiles}} 个文件, {{.Stats.ProductionStats.CodeLines}} 行代码</div>
+            <div class="summary-item"><span class="summary-label">测试比:</span> {{printf "%.2f" .Stats.TestRatio}}</div>
+        </div>
+        <h4>按顶层目录统计</h4>
+        <table id="tests-table" class="display">
+            <thead>
+                <tr>
+                    <th>目录</th>
+                    <th>测试文件</th>
+                    <th>测试代码行</th>
+                    <th>非测试文件</th>
+                    <th>非测试代码行</th>
+                    <th>测试比</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .TestDirs}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.TestStats.TotalFiles}}</td>
+                    <td>{{.TestStats.CodeLines}}</td>
+                    <td>{{.ProductionStats.TotalFiles}}</td>
+                    <td>{{.ProductionStats.CodeLines}}</td>
+                    <td>{{printf "%.2f" .TestRatio}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 许可证区域 -->
+    {{if or .Licenses .Stats.MissingLicense .Stats.LicenseFiles}}
+    <div id="section-licenses" class="section">
+        <div class="summary">
+            <h3>许可证</h3>
+            <p>通过文件开头注释块中的 SPDX-License-Identifier 和常见许可证文本识别，标记语言和数据格式不检查</p>
+            <div class="summary-item"><span class="summary-label">声明许可证的文件:</span> {{range .Licenses}}{{.Name}} {{.Count}} 个 {{else}}无{{end}}</div>
+            <div class="summary-item"><span class="summary-label">缺少许可证头:</span> {{len .Stats.MissingLicense}} 个文件（不含生成、第三方和压缩的文件）</div>
+        </div>
+        {{if .Stats.LicenseFiles}}
+        <h4>许可证文件</h4>
+        <table id="license-files-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件</th>
+                    <th>许可证</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Stats.LicenseFiles}}
+                <tr>
+                    <td>{{.Path}}</td>
+                    <td>{{.License}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{if .Licenses}}
+        <h4>按许可证统计</h4>
+        <table id="licenses-table" class="display">
+            <thead>
+                <tr>
+                    <th>许可证</th>
+                    <th>文件数</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Licenses}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Count}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{if .Stats.MissingLicense}}
+        <h4>缺少许可证头的文件</h4>
+        <table id="missing-license-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Stats.MissingLicense}}
+                <tr>
+                    <td>{{.}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+    </div>
+    {{end}}
+
+    <!-- 压缩文件区域 -->
+    {{if .MinifiedFiles}}
+    <div id="section-minified" class="section">
+        <table id="minified-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件路径</th>
+                    <th>语言</th>
+                    <th>大小(KB)</th>
+                    <th>总行数</th>
+                    <th>平均行长度</th>
+                    <th>最长行</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .MinifiedFiles}}
+                <tr>
+                    <td>{{.Path}}</td>
+                    <td>{{.Language}}</td>
+                    <td>{{printf "%.2f" (divideBy .TotalSize 1024)}}</td>
+                    <td>{{.TotalLines}}</td>
+                    <td>{{printf "%.1f" .AvgLineLength}}</td>
+                    <td>{{.MaxLineLength}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 无法解码的文件区域 -->
+    {{if .Stats.UndecodableFiles}}
+    <div id="section-undecodable" class="section">
+        <div class="summary">
+            <h3>无法解码的文件</h3>
+            <p>以下文件既不是有效的 UTF-8/UTF-16，也无法按 GBK 解码，未计入统计</p>
+        </div>
+        <table id="undecodable-table" class="display">
+            <thead>
+                <tr>
+                    <th>文件路径</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Stats.UndecodableFiles}}
+                <tr>
+                    <td>{{.}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- 二进制文件区域 -->
+    {{if .SortedBinaryExts}}
+    <div id="section-binaries" class="section">
+        <table id="binary-table" class="display">
+            <thead>
+                <tr>
+                    <th>扩展名</th>
+                    <th>文件数</th>
+                    <th>总大小(KB)</th>
+                    <th>平均大小(KB)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .SortedBinaryExts}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Stats.TotalFiles}}</td>
+                    <td>{{printf "%.2f" (divideBy .Stats.TotalSize 1024)}}</td>
+                    <td>{{printf "%.2f" (divideBy (divideBy .Stats.TotalSize .Stats.TotalFiles) 1024)}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
+    <!-- Go 代码区域 -->
+    {{if .GoPackages}}
+    <div id="section-go" class="section">
+        <div class="summary">
+            <h3>Go 代码结构</h3>
+            {{with .Stats.GoStats}}
+            <div class="summary-item"><span class="summary-label">包数量:</span> {{len $.GoPackages}} 个包</div>
+            <div class="summary-item"><span class="summary-label">函数/方法:</span> {{.Functions}} 个函数, {{.Methods}} 个方法</div>
+            <div class="summary-item"><span class="summary-label">类型:</span> {{.Interfaces}} 个接口, {{.Structs}} 个结构体</div>
+            <div class="summary-item"><span class="summary-label">标识符:</span> {{.Exported}} 个导出, {{.Unexported}} 个未导出</div>
+            <div class="summary-item"><span class="summary-label">文档覆盖率:</span> {{.ExportedDocumented}}/{{.Exported}} ({{printf "%.1f%%" (multiply .DocCoverage 100)}})</div>
+            <div class="summary-item"><span class="summary-label">圈复杂度:</span> 平均 {{printf "%.2f" .AvgCyclomatic}}, 最大 {{.MaxCyclomatic}}</div>
+            <div class="summary-item"><span class="summary-label">认知复杂度:</span> 平均 {{printf "%.2f" .AvgCognitive}}, 最大 {{.MaxCognitive}}</div>
+            {{end}}
+        </div>
+        <table id="go-packages-table" class="display">
+            <thead>
+                <tr>
+                    <th>目录</th>
+                    <th>包名</th>
+                    <th>文件数</th>
+                    <th>函数</th>
+                    <th>方法</th>
+                    <th>接口</th>
+                    <th>结构体</th>
+                    <th>导出</th>
+                    <th>未导出</th>
+                    <th>文档覆盖率</th>
+                    <th>平均圈复杂度</th>
+                    <th>最大圈复杂度</th>
+                    <th>平均认知复杂度</th>
+                    <th>最大认知复杂度</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .GoPackages}}
+                <tr>
+                    <td>{{.Dir}}</td>
+                    <td>{{.Name}}</td>
+                    <td>{{.Files}}</td>
+                    <td>{{.Functions}}</td>
+                    <td>{{.Methods}}</td>
+                    <td>{{.Interfaces}}</td>
+                    <td>{{.Structs}}</td>
+                    <td>{{.Exported}}</td>
+                    <td>{{.Unexported}}</td>
+                    <td>{{printf "%.1f%%" (multiply .DocCoverage 100)}}</td>
+                    <td>{{printf "%.2f" .AvgCyclomatic}}</td>
+                    <td>{{.MaxCyclomatic}}</td>
+                    <td>{{printf "%.2f" .AvgCognitive}}</td>
+                    <td>{{.MaxCognitive}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+    </div>
+    {{end}}
+
     <!-- 文件浏览器区域 -->
     <div id="section-file-browser" class="section">
         <div class="summary">
@@ -841,7 +1383,7 @@
         $(document).ready(function() {
             // 为所有table.display表格启用DataTables排序功能
             // 使用 $.fn.dataTable.isDataTable 检查表格是否已经初始化
-            $('table.display:not(#files-dashboard)').each(function() {
+            $('table.display:not(#files-dashboard):not(#markers-table):not(#missing-license-table)').each(function() {
                 if (!$.fn.dataTable.isDataTable(this)) {
                     $(this).DataTable({
                         paging: false,
@@ -893,17 +1435,19 @@
         new Chart(compositionCtx, {
             type: 'pie',
             data: {
-                labels: ['代码行', '注释行', '空白行'],
+                labels: ['代码行', '注释行', '文档行', '空白行'],
                 datasets: [{
-                    data: [{{.Stats.CodeLines}}, {{.Stats.CommentLines}}, {{.Stats.BlankLines}}],
+                    data: [{{.Stats.CodeLines}}, {{.Stats.CommentLines}}, {{.Stats.DocLines}}, {{.Stats.BlankLines}}],
                     backgroundColor: [
                         'rgba(54, 162, 235, 0.7)',
                         'rgba(255, 205, 86, 0.7)',
+                        'rgba(75, 192, 192, 0.7)',
                         'rgba(201, 203, 207, 0.7)'
                     ],
                     borderColor: [
                         'rgb(54, 162, 235)',
                         'rgb(255, 205, 86)',
+                        'rgb(75, 192, 192)',
                         'rgb(201, 203, 207)'
                     ],
                     borderWidth: 1
@@ -956,14 +1500,34 @@
             "{{$file.Path}}": {
                 path: "{{$file.Path}}",
                 language: "{{if $file.Language}}{{$file.Language}}{{else}}未识别{{end}}",
+                tags: [{{if $file.IsTest}}'测试代码',{{end}}{{if $file.IsGenerated}}'生成代码',{{end}}{{if $file.IsMinified}}'压缩代码',{{end}}{{if $file.IsVendored}}'第三方代码',{{end}}{{if $file.IsDocumentation}}'文档',{{end}}],
                 extension: "{{if ext $file.Path}}{{ext $file.Path}}{{else}}(无扩展名){{end}}",
+                encoding: "{{$file.Encoding}}",
                 size: {{printf "%.2f" (divideBy $file.TotalSize 1024)}},
                 totalLines: {{$file.TotalLines}},
                 codeLines: {{$file.CodeLines}},
                 commentLines: {{$file.CommentLines}},
+                docLines: {{$file.DocLines}},
                 blankLines: {{$file.BlankLines}},
+                mixedLines: {{$file.MixedLines}},
                 commentRatio: {{printf "%.2f" (commentRatio $file.CommentLines $file.CodeLines)}},
-                avgLineLength: {{printf "%.1f" $file.AvgLineLength}}
+                functionCount: {{$file.FunctionCount}},
+                avgFunctionLength: {{printf "%.1f" $file.AvgFunctionLength}},
+                maxFunctionLength: {{$file.MaxFunctionLength}},
+                duplicatedLines: {{$file.DuplicatedLines}},
+                duplicationRatio: {{printf "%.1f" (multiply $file.DuplicationRatio 100)}},
+                markers: {{len $file.Markers}},
+                license: "{{if $file.License}}{{$file.License}}{{else if $file.MissingLicense}}缺少许可证头{{else}}-{{end}}",
+                maxNestingDepth: {{$file.MaxNestingDepth}},
+                avgNestingDepth: {{printf "%.2f" $file.AvgNestingDepth}},
+                complexity: {{$file.Complexity}},
+                complexityDensity: {{printf "%.3f" $file.ComplexityDensity}},
+                avgLineLength: {{printf "%.1f" $file.AvgLineLength}},
+                maxLineLength: {{$file.MaxLineLength}},
+                longLines: {{$file.LongLines}},
+                notebook: {{if $file.Notebook}}{kernel: "{{js $file.Notebook.Kernel}}", codeCells: {{$file.Notebook.CodeCells}}, markdownCells: {{$file.Notebook.MarkdownCells}}, rawCells: {{$file.Notebook.RawCells}}}{{else}}null{{end}},
+                go: {{if $file.Go}}{pkg: "{{$file.Go.Name}}", functions: {{$file.Go.Functions}}, methods: {{$file.Go.Methods}}, exported: {{$file.Go.Exported}}, documented: {{$file.Go.ExportedDocumented}}, avgCyclomatic: {{printf "%.2f" $file.Go.AvgCyclomatic}}, maxCyclomatic: {{$file.Go.MaxCyclomatic}}, avgCognitive: {{printf "%.2f" $file.Go.AvgCognitive}}, maxCognitive: {{$file.Go.MaxCognitive}}}{{else}}null{{end}},
+                regions: [{{range $lang, $region := $file.Regions}}{language: "{{$lang}}", codeLines: {{$region.CodeLines}}, commentLines: {{$region.CommentLines}}, docLines: {{$region.DocLines}}, blankLines: {{$region.BlankLines}}},{{end}}]
             }{{if lt $i (subtract (len $.Stats.FileStats) 1)}},{{end}}
             {{end}}
         };
@@ -1055,8 +1619,35 @@
             html += '<div class="info-group">' +
                     '<span class="info-label">语言:</span>' + file.language + 
                     '<span class="info-label" style="margin-left:20px;">扩展名:</span>' + file.extension + 
+                    '<span class="info-label" style="margin-left:20px;">编码:</span>' + file.encoding + 
+                    '<span class="info-label" style="margin-left:20px;">许可证:</span>' + file.license + 
+                    (file.tags.length > 0 ? '<span class="info-label" style="margin-left:20px;">标记:</span>' + file.tags.join(', ') : '') +
                     '</div>';
             
+            // Go 代码结构
+            if (file.go) {
+                html += '<div class="info-group">' +
+                        '<span class="info-label">包名:</span>' + file.go.pkg +
+                        '<span class="info-label" style="margin-left:20px;">函数:</span>' + file.go.functions +
+                        '<span class="info-label" style="margin-left:20px;">方法:</span>' + file.go.methods +
+                        '<span class="info-label" style="margin-left:20px;">导出标识符文档:</span>' + file.go.documented + '/' + file.go.exported +
+                        '</div>' +
+                        '<div class="info-group">' +
+                        '<span class="info-label">圈复杂度:</span>平均 ' + file.go.avgCyclomatic + ', 最大 ' + file.go.maxCyclomatic +
+                        '<span class="info-label" style="margin-left:20px;">认知复杂度:</span>平均 ' + file.go.avgCognitive + ', 最大 ' + file.go.maxCognitive +
+                        '</div>';
+            }
+            
+            // Notebook 单元格统计
+            if (file.notebook) {
+                html += '<div class="info-group">' +
+                        '<span class="info-label">内核:</span>' + file.notebook.kernel +
+                        '<span class="info-label" style="margin-left:20px;">代码单元格:</span>' + file.notebook.codeCells +
+                        '<span class="info-label" style="margin-left:20px;">Markdown 单元格:</span>' + file.notebook.markdownCells +
+                        '<span class="info-label" style="margin-left:20px;">原始单元格:</span>' + file.notebook.rawCells +
+                        '</div>';
+            }
+            
             html += '<div class="metrics">';
             
             // 文件大小指标
@@ -1083,26 +1674,90 @@
                     '<div class="metric-name">注释行</div>' +
                     '</div>';
             
+            // 文档行指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.docLines + '</div>' +
+                    '<div class="metric-name">文档行</div>' +
+                    '</div>';
+            
             // 空白行指标
             html += '<div class="metric-box">' +
                     '<div class="metric-value">' + file.blankLines + '</div>' +
                     '<div class="metric-name">空白行</div>' +
                     '</div>';
             
+            // 混合行指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.mixedLines + '</div>' +
+                    '<div class="metric-name">混合行</div>' +
+                    '</div>';
+            
             // 注释比例指标
             html += '<div class="metric-box">' +
                     '<div class="metric-value">' + file.commentRatio + '</div>' +
                     '<div class="metric-name">注释比例</div>' +
                     '</div>';
             
+            // 函数长度指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.functionCount + ' (平均 ' + file.avgFunctionLength + ' 行, 最长 ' + file.maxFunctionLength + ' 行)</div>' +
+                    '<div class="metric-name">函数</div>' +
+                    '</div>';
+            
+            // 重复代码指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.duplicatedLines + ' (' + file.duplicationRatio + '%)</div>' +
+                    '<div class="metric-name">重复行</div>' +
+                    '</div>';
+            
+            // 代码标记指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.markers + '</div>' +
+                    '<div class="metric-name">代码标记</div>' +
+                    '</div>';
+            
+            // 嵌套深度指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.maxNestingDepth + ' (平均 ' + file.avgNestingDepth + ')</div>' +
+                    '<div class="metric-name">最大嵌套深度</div>' +
+                    '</div>';
+            
+            // 复杂度指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.complexity + ' (' + file.complexityDensity + '/行)</div>' +
+                    '<div class="metric-name">复杂度</div>' +
+                    '</div>';
+            
             // 平均行长指标
             html += '<div class="metric-box">' +
                     '<div class="metric-value">' + file.avgLineLength + '</div>' +
                     '<div class="metric-name">平均行长度(字符)</div>' +
                     '</div>';
             
+            // 最长行指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.maxLineLength + '</div>' +
+                    '<div class="metric-name">最长行(字符)</div>' +
+                    '</div>';
+            
+            // 超长行指标
+            html += '<div class="metric-box">' +
+                    '<div class="metric-value">' + file.longLines + '</div>' +
+                    '<div class="metric-name">超长行</div>' +
+                    '</div>';
+            
             html += '</div>';
             
+            // 内嵌语言区域
+            if (file.regions.length > 0) {
+                html += '<table class="region-table"><thead><tr><th>内嵌语言</th><th>代码行</th><th>注释行</th><th>文档行</th><th>空白行</th></tr></thead><tbody>';
+                file.regions.forEach(region => {
+                    html += '<tr><td>' + region.language + '</td><td>' + region.codeLines + '</td><td>' +
+                            region.commentLines + '</td><td>' + region.docLines + '</td><td>' + region.blankLines + '</td></tr>';
+                });
+                html += '</tbody></table>';
+            }
+            
             // 添加文件组成饼图
             html += '<div class="file-mini-chart">' +
                     '<canvas id="fileCompositionChart"></canvas>' +
@@ -1125,17 +1780,19 @@
                     new Chart(ctx, {
                         type: 'pie',
                         data: {
-                            labels: ['代码行', '注释行', '空白行'],
+                            labels: ['代码行', '注释行', '文档行', '空白行'],
                             datasets: [{
-                                data: [file.codeLines, file.commentLines, file.blankLines],
+                                data: [file.codeLines, file.commentLines, file.docLines, file.blankLines],
                                 backgroundColor: [
                                     'rgba(54, 162, 235, 0.7)',
                                     'rgba(255, 205, 86, 0.7)',
+                                    'rgba(75, 192, 192, 0.7)',
                                     'rgba(201, 203, 207, 0.7)'
                                 ],
                                 borderColor: [
                                     'rgb(54, 162, 235)',
                                     'rgb(255, 205, 86)',
+                                    'rgb(75, 192, 192)',
                                     'rgb(201, 203, 207)'
                                 ],
                                 borderWidth: 1
@@ -1203,6 +1860,21 @@
             // 初始化文件详情区域
             $('.file-details').html('<div class="no-file-selected"><p>请从左侧目录树中选择一个文件查看详情</p></div>');
             
+            // 代码标记和缺少许可证头的文件列表可能很长，需要搜索和分页
+            $('#markers-table, #missing-license-table').each(function() {
+                if ($.fn.dataTable.isDataTable(this)) {
+                    return;
+                }
+                $(this).DataTable({
+                    paging: true,
+                    pageLength: 50,
+                    searching: true,
+                    info: true,
+                    order: [],
+                    stripeClasses: []
+                });
+            });
+            
             // 为贡献者表格初始化 DataTable - 避免重复初始化
             if (document.getElementById('contributors-table') && !$.fn.dataTable.isDataTable('#contributors-table')) {
                 $('#contributors-table').DataTable({
@@ -28,13 +28,45 @@ type Stat struct {
 	BlankLines    int     // 空白行数
 	AvgBlankLines float64 // 平均空白行数
 
+	// 文档行数（如 Python 的文档字符串、Notebook 的 Markdown 单元格），不计入注释行数
+	DocLines int // 文档行数
+
+	// 混合行数（同时包含代码与注释，如 x := 1 // reset）
+	MixedLines int // 混合行数
+
+	// 注释标记数（如 TODO、FIXME）
+	Markers int // 注释标记数
+
 	// 代码密度
 	CodeDensity    float64 // 代码密度: 代码行数/总行数
 	CommentDensity float64 // 注释密度: 注释行数/总行数
 	CommentRatio   float64 // 注释比例: 注释行数/代码行数
 
+	// 复杂度（按分支关键字和逻辑运算符估算）
+	Complexity        int     // 复杂度
+	ComplexityDensity float64 // 复杂度密度: 复杂度/代码行数
+
+	// 函数长度
+	FunctionCount     int     // 函数数
+	FunctionLines     int     // 函数总行数
+	AvgFunctionLength float64 // 平均函数长度
+	MaxFunctionLength int     // 最长函数的行数
+	FunctionsOver50   int     // 超过 50 行的函数数
+	FunctionsOver100  int     // 超过 100 行的函数数
+	FunctionsOver200  int     // 超过 200 行的函数数
+
+	// 嵌套深度（C 系语言为花括号深度，Python、YAML 为缩进层级）
+	MaxNestingDepth   int     // 最大嵌套深度
+	TotalNestingDepth int     // 各代码行的嵌套深度之和
+	NestedLines       int     // 计算了嵌套深度的代码行数
+	AvgNestingDepth   float64 // 平均嵌套深度
+
 	// 平均每行字符数
 	AvgLineLength float64 // 平均每行字符数
+
+	// 行长度
+	MaxLineLength int // 最长行的字符数
+	LongLines     int // 超过阈值的超长行数
 }
 
 // 合并统计信息
@@ -46,6 +78,21 @@ func (s *Stat) Merge(other *Stat) {
 	s.CodeLines += other.CodeLines
 	s.CommentLines += other.CommentLines
 	s.BlankLines += other.BlankLines
+	s.DocLines += other.DocLines
+	s.MixedLines += other.MixedLines
+	s.Markers += other.Markers
+	s.MaxLineLength = max(s.MaxLineLength, other.MaxLineLength)
+	s.LongLines += other.LongLines
+	s.Complexity += other.Complexity
+	s.FunctionCount += other.FunctionCount
+	s.FunctionLines += other.FunctionLines
+	s.MaxFunctionLength = max(s.MaxFunctionLength, other.MaxFunctionLength)
+	s.FunctionsOver50 += other.FunctionsOver50
+	s.FunctionsOver100 += other.FunctionsOver100
+	s.FunctionsOver200 += other.FunctionsOver200
+	s.MaxNestingDepth = max(s.MaxNestingDepth, other.MaxNestingDepth)
+	s.TotalNestingDepth += other.TotalNestingDepth
+	s.NestedLines += other.NestedLines
 }
 
 // 计算平均值
@@ -58,6 +105,16 @@ func (s *Stat) CalculateAvg() {
 	s.AvgBlankLines = float64(s.BlankLines) / float64(s.TotalLines)
 	s.CodeDensity = float64(s.CodeLines) / float64(s.TotalLines)
 	s.CommentDensity = float64(s.CommentLines) / float64(s.TotalLines)
-	s.CommentRatio = float64(s.CommentLines) / float64(s.CodeLines)
 	s.AvgLineLength = float64(s.TotalChars) / float64(s.TotalLines)
+	// 只有文档行的区域（如 Notebook 的 Markdown 单元格）没有代码行
+	if s.CodeLines > 0 {
+		s.CommentRatio = float64(s.CommentLines) / float64(s.CodeLines)
+		s.ComplexityDensity = float64(s.Complexity) / float64(s.CodeLines)
+	}
+	if s.NestedLines > 0 {
+		s.AvgNestingDepth = float64(s.TotalNestingDepth) / float64(s.NestedLines)
+	}
+	if s.FunctionCount > 0 {
+		s.AvgFunctionLength = float64(s.FunctionLines) / float64(s.FunctionCount)
+	}
 }
@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// 测试文件的常见文件名模式（filepath.Match 语法，匹配文件名）
+var testFilePatterns = []string{
+	"*_test.go",
+	"test_*.py", "*_test.py", "conftest.py",
+	"*.test.js", "*.spec.js", "*.test.jsx", "*.spec.jsx", "*.test.mjs", "*.spec.mjs",
+	"*.test.ts", "*.spec.ts", "*.test.tsx", "*.spec.tsx",
+	"*Test.java", "*Tests.java", "*IT.java", "*Test.kt", "*Tests.kt", "*Spec.scala", "*Test.scala",
+	"*Tests.cs", "*Test.cs", "*Test.php", "*_spec.rb", "*_test.rb", "*_test.dart", "*_test.exs",
+	"*_test.c", "*_test.cc", "*_test.cpp", "*_unittest.cc", "*Tests.swift", "*Test.swift",
+}
+
+// 存放测试代码的常见目录名
+var testDirNames = []string{"test", "tests", "__tests__", "spec", "specs"}
+
+// 判断文件是否是测试代码，relPath 为相对于分析目录的路径
+// extraPatterns 为用户配置的额外模式，以 / 结尾的模式匹配目录名，其余模式匹配文件名
+func isTestFile(relPath string, extraPatterns []string) bool {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	name, dirs := parts[len(parts)-1], parts[:len(parts)-1]
+
+	for _, patterns := range [][]string{testFilePatterns, extraPatterns} {
+		for _, pattern := range patterns {
+			if dirPattern, ok := strings.CutSuffix(pattern, "/"); ok {
+				if slices.ContainsFunc(dirs, func(dir string) bool {
+					matched, _ := filepath.Match(dirPattern, dir)
+					return matched
+				}) {
+					return true
+				}
+				continue
+			}
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+
+	// Maven/Gradle 约定的 src/test 目录也包含在 test 目录中
+	return slices.ContainsFunc(dirs, func(dir string) bool {
+		return slices.Contains(testDirNames, strings.ToLower(dir))
+	})
+}
+
+// TestSplit 分别统计测试代码和非测试代码
+type TestSplit struct {
+	TestStats       *Stat // 测试代码的统计
+	ProductionStats *Stat // 非测试代码的统计
+}
+
+func newTestSplit() TestSplit {
+	return TestSplit{TestStats: &Stat{}, ProductionStats: &Stat{}}
+}
+
+// 按是否是测试代码合并统计
+func (t TestSplit) add(stat *Stat, isTest bool) {
+	if isTest {
+		t.TestStats.Merge(stat)
+	} else {
+		t.ProductionStats.Merge(stat)
+	}
+}
+
+// 计算测试代码和非测试代码的平均值
+func (t TestSplit) calculateAvg() {
+	for _, stat := range []*Stat{t.TestStats, t.ProductionStats} {
+		if stat.TotalFiles > 0 {
+			stat.CalculateAvg()
+		}
+	}
+}
+
+// TestRatio 返回测试代码与非测试代码的代码行数之比，没有非测试代码时为 0
+func (t TestSplit) TestRatio() float64 {
+	if t.ProductionStats.CodeLines == 0 {
+		return 0
+	}
+	return float64(t.TestStats.CodeLines) / float64(t.ProductionStats.CodeLines)
+}
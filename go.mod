@@ -8,6 +8,7 @@ require (
 	github.com/samber/lo v1.49.1
 	github.com/schollz/progressbar/v3 v3.18.0
 	github.com/spf13/cast v1.7.1
+	golang.org/x/text v0.21.0
 )
 
 require (
@@ -15,5 +16,4 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/term v0.28.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
 )
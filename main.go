@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/lllllan02/code-stats/analyzer"
@@ -21,9 +22,41 @@ var (
 	// 最大并发数
 	maxWorkersFlag = flag.Int("max-workers", 10, "Maximum number of concurrent workers")
 
+	// 自定义语言配置文件
+	languagesFlag = flag.String("languages", "", "JSON file with extra language definitions (merged over built-in ones and .code-stats.json)")
+
+	// 根据 .gitattributes 的 linguist 属性排除文件
+	excludeGeneratedFlag = flag.Bool("exclude-generated", false, "Exclude generated files (header markers, filename patterns or linguist-generated)")
+	excludeVendoredFlag  = flag.Bool("exclude-vendored", false, "Exclude files marked linguist-vendored in .gitattributes")
+	excludeDocsFlag      = flag.Bool("exclude-docs", false, "Exclude files marked linguist-documentation in .gitattributes")
+
+	// 排除压缩或打包后的文件
+	excludeMinifiedFlag = flag.Bool("exclude-minified", false, "Exclude minified and bundled files (e.g. *.min.js, webpack bundles)")
+
+	// 额外的测试文件模式
+	testPatternsFlag = flag.String("test-patterns", "", "Comma-separated list of extra test file patterns; patterns ending with / match directory names (e.g. *_it.go,e2e/)")
+
+	// 额外的注释标记
+	markersFlag = flag.String("markers", "", "Comma-separated list of extra comment markers to collect besides TODO, FIXME, HACK and XXX (e.g. NOTE,BUG)")
+
+	// 内容相同的文件只统计一次
+	skipDuplicateFilesFlag = flag.Bool("skip-duplicate-files", false, "Count files with identical content only once in the totals")
+
+	// 额外的生成代码文件名模式
+	generatedPatternsFlag = flag.String("generated-patterns", "", "Comma-separated list of extra filename patterns for generated files (e.g. *_gen.go)")
+
 	// 是否跟踪符号链接
 	followLinksFlag = flag.Bool("follow-links", false, "Follow symbolic links")
 
+	// 混合行（同时包含代码与注释）的计数方式
+	mixedLinesFlag = flag.String("mixed-lines", "code", "How to count lines with both code and comments: code, comment or both")
+
+	// 超长行阈值
+	longLineFlag = flag.Int("long-line", 120, "Lines longer than this many characters are counted as long lines (0 to disable)")
+
+	// 重复代码检测的最少连续行数
+	dupWindowFlag = flag.Int("dup-window", 6, "Minimum number of consecutive code lines to count as duplicated code (0 to disable)")
+
 	// 是否开启详细日志
 	verboseFlag = flag.Bool("verbose", false, "Show verbose output")
 
@@ -54,6 +87,10 @@ func printUsage() {
 	fmt.Println("  code-stats -path=/path/to/code -exclude-dirs=node_modules,vendor")
 	fmt.Println("\n  # 生成报告并保存到指定文件")
 	fmt.Println("  code-stats -output=report.html")
+	fmt.Println("\n  # 将同时包含代码与注释的行同时计入代码行和注释行")
+	fmt.Println("  code-stats -mixed-lines=both")
+	fmt.Println("\n  # 加载自定义语言定义")
+	fmt.Println("  code-stats -languages=languages.json")
 	fmt.Println("\n  # 只显示前50个最大的文件")
 	fmt.Println("  code-stats -top=50")
 }
@@ -65,11 +102,32 @@ func main() {
 		return
 	}
 
-	
-
 	options := analyzer.DefaultOptions()
+	mixedLinePolicy, err := analyzer.ParseMixedLinePolicy(*mixedLinesFlag)
+	if err != nil {
+		analyzer.PrintError("参数错误: %v", err)
+		return
+	}
+	options.MixedLinePolicy = mixedLinePolicy
+	options.LongLineThreshold = *longLineFlag
+	options.DuplicateWindow = *dupWindowFlag
 	options.MaxWorkers = *maxWorkersFlag
 	options.FollowLinks = *followLinksFlag
+	options.LanguageConfig = *languagesFlag
+	options.ExcludeGenerated = *excludeGeneratedFlag
+	options.ExcludeVendored = *excludeVendoredFlag
+	options.ExcludeDocumentation = *excludeDocsFlag
+	options.ExcludeMinified = *excludeMinifiedFlag
+	options.SkipDuplicateFiles = *skipDuplicateFilesFlag
+	if *testPatternsFlag != "" {
+		options.TestPatterns = splitList(*testPatternsFlag)
+	}
+	if *markersFlag != "" {
+		options.CommentMarkers = append(slices.Clone(options.CommentMarkers), splitList(*markersFlag)...)
+	}
+	if *generatedPatternsFlag != "" {
+		options.GeneratedPatterns = splitList(*generatedPatternsFlag)
+	}
 	if *excludeDirsFlag != "" {
 		options.ExcludeDirs = strings.Split(*excludeDirsFlag, ",")
 	}
@@ -83,7 +141,6 @@ func main() {
 		analyzer.PrintError("分析失败: %v", err)
 		return
 	}
-	
 
 	// 设置报告数据 - 从默认值开始，然后覆盖需要的字段
 	reportData := analyzer.DefaultReportData(stats)
@@ -103,3 +160,14 @@ func main() {
 
 	analyzer.PrintInfo("报告已生成: %s", reportData.OutputFile)
 }
+
+// 按逗号拆分列表参数，去除每一项首尾的空白并忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}